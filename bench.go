@@ -0,0 +1,148 @@
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// SizeClass is a coarse measure of the size of a fixture, to pick
+// representative inputs for benchmarks. It is based on the size of the
+// fixture's objects once inflated and with deltas resolved, see
+// Fixture.Size.
+type SizeClass int
+
+const (
+	// SizeUnknown is the size of fixtures missing from the generated
+	// metadata. As go generate fails on missing data, it means the
+	// generated files are stale.
+	SizeUnknown SizeClass = iota
+	// SizeSmall is for fixtures whose objects take less than 512 KiB, e.g.
+	// basic.git.
+	SizeSmall
+	// SizeMedium is for fixtures whose objects take less than 4 MiB.
+	SizeMedium
+	// SizeLarge is for fixtures whose objects take 4 MiB or more, e.g.
+	// spinnaker.
+	SizeLarge
+)
+
+const (
+	smallSizeLimit  = 512 << 10
+	mediumSizeLimit = 4 << 20
+)
+
+//nolint:gochecknoglobals
+var sizeClassNames = map[SizeClass]string{
+	SizeUnknown: "unknown",
+	SizeSmall:   "small",
+	SizeMedium:  "medium",
+	SizeLarge:   "large",
+}
+
+func (c SizeClass) String() string {
+	if name, ok := sizeClassNames[c]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("SizeClass(%d)", int(c))
+}
+
+// Size returns the size class of the fixture, from the total size of its
+// objects once inflated and with deltas resolved: those of its packfile or,
+// if it has none, those of its .git directory or of the repositories of its
+// worktree. Thin packs count their deltas as stored.
+//
+// Sizes are computed when generating the package, and by Builder.Packfile
+// for the fixtures it returns.
+func (f *Fixture) Size() SizeClass {
+	size, ok := f.objectBytes, f.fs != nil
+	if !ok {
+		size, ok = fixtureObjectBytes[f.sizeKey()]
+	}
+
+	switch {
+	case !ok:
+		return SizeUnknown
+	case size < smallSizeLimit:
+		return SizeSmall
+	case size < mediumSizeLimit:
+		return SizeMedium
+	default:
+		return SizeLarge
+	}
+}
+
+// sizeKey returns the key of the fixture in fixtureObjectBytes.
+func (f *Fixture) sizeKey() string {
+	switch {
+	case f.PackfileHash != "":
+		return "pack-" + f.PackfileHash
+	case f.DotGitHash != "":
+		return "git-" + f.DotGitHash
+	case f.WorktreeHash != "":
+		return "worktree-" + f.WorktreeHash
+	}
+
+	return ""
+}
+
+// BySize returns the fixtures of the given size class.
+func BySize(class SizeClass) Fixtures {
+	return fixtures.BySize(class)
+}
+
+func (g Fixtures) BySize(class SizeClass) Fixtures {
+	return g.filter(func(f *Fixture) bool { return f.Size() == class })
+}
+
+// BenchInput is the data of a fixture preloaded by Bench.
+type BenchInput struct {
+	// Pack is the content of the packfile.
+	Pack []byte
+	// Idx is the content of the idx file, nil if the fixture has none.
+	Idx []byte
+}
+
+// Bench runs fn as a sub-benchmark for each fixture of g, named as in Run.
+// The packfile and idx file are loaded before the timer is reset, and the
+// packfile size is reported as the bytes processed per operation. Fixtures
+// without a packfile are skipped.
+func (g Fixtures) Bench(b *testing.B, fn func(*testing.B, *Fixture, BenchInput)) {
+	b.Helper()
+
+	for _, f := range g {
+		b.Run(f.runName(), func(b *testing.B) {
+			b.Helper()
+
+			if f.PackfileHash == "" {
+				b.Skipf("fixture %s has no packfile", f.runName())
+			}
+
+			in, err := f.benchInput()
+			if err != nil {
+				b.Fatalf("loading fixture %s: %v", f.runName(), err)
+			}
+
+			b.SetBytes(int64(len(in.Pack)))
+			b.ResetTimer()
+
+			fn(b, f, in)
+		})
+	}
+}
+
+func (f *Fixture) benchInput() (BenchInput, error) {
+	pack, err := f.readFile(f.packfilePath("pack"))
+	if err != nil {
+		return BenchInput{}, err
+	}
+
+	idx, err := f.readFile(f.packfilePath("idx"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return BenchInput{}, err
+	}
+
+	return BenchInput{Pack: pack, Idx: idx}, nil
+}
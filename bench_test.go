@@ -0,0 +1,100 @@
+package fixtures_test
+
+import (
+	"hash/crc32"
+	"testing"
+
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want fixtures.SizeClass
+	}{
+		{name: "basic-ofs-delta", want: fixtures.SizeSmall},
+		{name: "basic-reftable", want: fixtures.SizeSmall},
+		{name: "desk", want: fixtures.SizeMedium},
+		{name: "go-git", want: fixtures.SizeLarge},
+		{name: "basic-worktree", want: fixtures.SizeSmall},
+		{name: "litemock", want: fixtures.SizeMedium},
+		{name: "sha256-midx", want: fixtures.SizeSmall},
+		{name: "spinnaker", want: fixtures.SizeLarge},
+		{name: "rumprun-xen", want: fixtures.SizeLarge},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := fixtures.ByName(tc.name)
+			require.NotNil(t, f)
+			assert.Equal(t, tc.want, f.Size())
+		})
+	}
+}
+
+func TestSizeGenerated(t *testing.T) {
+	t.Parallel()
+
+	f, err := newHistoryBuilder(t, "sha1", 2).Packfile()
+	require.NoError(t, err)
+	assert.Equal(t, fixtures.SizeSmall, f.Size())
+	assert.Equal(t, fixtures.SizeSmall, f.Clone().Size())
+}
+
+func TestBySize(t *testing.T) {
+	t.Parallel()
+
+	total := 0
+
+	for _, class := range []fixtures.SizeClass{
+		fixtures.SizeUnknown, fixtures.SizeSmall, fixtures.SizeMedium, fixtures.SizeLarge,
+	} {
+		got := fixtures.BySize(class)
+		for _, f := range got {
+			assert.Equal(t, class, f.Size(), f.Name)
+		}
+
+		total += len(got)
+	}
+
+	assert.Len(t, fixtures.All(), total)
+
+	got, err := fixtures.Select("packfile && size==large")
+	require.NoError(t, err)
+	assert.Equal(t, fixtures.ByTag(fixtures.TagPackfile).BySize(fixtures.SizeLarge), got)
+	assert.NotEmpty(t, got)
+
+	got, err = fixtures.Select("size==unknown")
+	require.NoError(t, err)
+	assert.Equal(t, fixtures.BySize(fixtures.SizeUnknown), got)
+
+	_, err = fixtures.Select("size==huge")
+	require.ErrorIs(t, err, fixtures.ErrInvalidQuery)
+}
+
+func TestSizeClassString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "small", fixtures.SizeSmall.String())
+	assert.Equal(t, "large", fixtures.SizeLarge.String())
+	assert.Equal(t, "SizeClass(42)", fixtures.SizeClass(42).String())
+}
+
+func BenchmarkBench(b *testing.B) {
+	g := fixtures.Fixtures{fixtures.ByName("basic-ofs-delta"), fixtures.ByName("desk"), fixtures.ByName("spinnaker")}
+
+	g.Bench(b, func(b *testing.B, _ *fixtures.Fixture, in fixtures.BenchInput) {
+		require.NotEmpty(b, in.Pack)
+		require.NotEmpty(b, in.Idx)
+
+		for b.Loop() {
+			crc32.ChecksumIEEE(in.Pack)
+		}
+	})
+}
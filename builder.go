@@ -0,0 +1,486 @@
+package fixtures
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+)
+
+// Git file modes to be used in TreeEntry.Mode.
+const (
+	ModeDir        = object.ModeDir
+	ModeRegular    = object.ModeRegular
+	ModeExecutable = object.ModeExecutable
+	ModeSymlink    = object.ModeSymlink
+	ModeSubmodule  = object.ModeSubmodule
+)
+
+var (
+	ErrUnknownObject    = errors.New("unknown object")
+	ErrInvalidRef       = errors.New("invalid reference")
+	ErrInvalidTreeEntry = errors.New("invalid tree entry")
+)
+
+// defaultSignature is used whenever a Signature is left empty, so that
+// builder output is deterministic.
+//
+//nolint:gochecknoglobals
+var defaultSignature = Signature{
+	Name:  "go-git-fixtures",
+	Email: "fixtures@go-git.dev",
+	When:  time.Unix(1136214245, 0).UTC(),
+}
+
+// TreeEntry is a single entry of a tree created with Builder.Tree.
+type TreeEntry struct {
+	// Name is the base name of the entry. It must not be empty, "." or "..",
+	// nor contain a slash or NUL byte, and must be unique within the tree.
+	Name string
+	// Mode is the git file mode of the entry (e.g. ModeRegular, ModeDir).
+	Mode uint32
+	// Hash is the hex-encoded hash of the object the entry points to.
+	Hash string
+}
+
+// Signature identifies the author, committer or tagger of an object.
+type Signature struct {
+	Name  string
+	Email string
+	When  time.Time
+}
+
+// CommitSpec describes a commit to be created with Builder.Commit.
+type CommitSpec struct {
+	// Tree is the hex-encoded hash of the root tree.
+	Tree string
+	// Parents are the hex-encoded hashes of the parent commits.
+	Parents []string
+	// Author defaults to a fixed signature when empty.
+	Author Signature
+	// Committer defaults to Author when empty.
+	Committer Signature
+	Message   string
+}
+
+// TagSpec describes an annotated tag to be created with Builder.Tag.
+type TagSpec struct {
+	// Name is the short name of the tag (e.g. "v1.0.0").
+	Name string
+	// Target is the hex-encoded hash of the tagged object, which must have
+	// been previously added to the Builder.
+	Target string
+	// Tagger defaults to a fixed signature when empty.
+	Tagger  Signature
+	Message string
+}
+
+type builderObject struct {
+	typ     object.Type
+	hash    string
+	content []byte
+}
+
+// Builder synthesizes git repositories for tests. Objects are declared in Go
+// and materialized into a billy.Filesystem laid out like the output of
+// Fixture.DotGit.
+//
+// Methods that add objects return the hash of the new object so they can be
+// chained into trees, commits and tags. Errors are deferred and returned by
+// Build.
+type Builder struct {
+	objectFormat string
+	objects      []builderObject
+	index        map[string]int
+	refs         map[string]string
+	symbolicRefs map[string]string
+	err          error
+}
+
+// NewBuilder returns a Builder producing objects in the given object format
+// ("sha1" or "sha256").
+func NewBuilder(objectFormat string) *Builder {
+	b := &Builder{
+		objectFormat: objectFormat,
+		objects:      nil,
+		index:        map[string]int{},
+		refs:         map[string]string{},
+		symbolicRefs: map[string]string{},
+		err:          nil,
+	}
+
+	_, err := object.HashSize(objectFormat)
+	b.setErr(err)
+
+	return b
+}
+
+// Blob adds a blob with the given content and returns its hash.
+func (b *Builder) Blob(content []byte) string {
+	return b.add(object.BlobType, bytes.Clone(content))
+}
+
+// Tree adds a tree holding entries and returns its hash. Entries are sorted
+// the way git does, so they can be given in any order.
+func (b *Builder) Tree(entries ...TreeEntry) string {
+	names := make(map[string]bool, len(entries))
+	oe := make([]object.TreeEntry, 0, len(entries))
+
+	for _, e := range entries {
+		err := checkTreeEntryName(e.Name)
+		if err == nil && names[e.Name] {
+			err = fmt.Errorf("%w: duplicate name %q", ErrInvalidTreeEntry, e.Name)
+		}
+
+		if err != nil {
+			b.setErr(fmt.Errorf("tree: %w", err))
+
+			return ""
+		}
+
+		names[e.Name] = true
+		oe = append(oe, object.TreeEntry{Name: e.Name, Mode: e.Mode, Hash: e.Hash})
+	}
+
+	content, err := object.EncodeTree(b.objectFormat, oe)
+	if err != nil {
+		b.setErr(fmt.Errorf("tree: %w", err))
+
+		return ""
+	}
+
+	return b.add(object.TreeType, content)
+}
+
+// Commit adds a commit and returns its hash. Hashes may be given in
+// uppercase, and are written in lowercase, as git does.
+func (b *Builder) Commit(c CommitSpec) string {
+	tree, err := b.normalizeHash(c.Tree)
+	if err != nil {
+		b.setErr(fmt.Errorf("commit: %w", err))
+
+		return ""
+	}
+
+	parents := make([]string, 0, len(c.Parents))
+
+	for _, p := range c.Parents {
+		parent, err := b.normalizeHash(p)
+		if err != nil {
+			b.setErr(fmt.Errorf("commit: %w", err))
+
+			return ""
+		}
+
+		parents = append(parents, parent)
+	}
+
+	author := orDefault(c.Author, defaultSignature)
+	committer := orDefault(c.Committer, author)
+
+	return b.add(object.CommitType, object.EncodeCommit(object.Commit{
+		Tree:      tree,
+		Parents:   parents,
+		Author:    object.Signature(author),
+		Committer: object.Signature(committer),
+		Message:   c.Message,
+	}))
+}
+
+// Tag adds an annotated tag object and returns its hash. No reference is
+// created; use Ref to point refs/tags/<name> at it. The target may be given
+// in uppercase, and is written in lowercase.
+func (b *Builder) Tag(t TagSpec) string {
+	target, err := b.normalizeHash(t.Target)
+	if err != nil {
+		b.setErr(fmt.Errorf("tag %q: %w", t.Name, err))
+
+		return ""
+	}
+
+	i, ok := b.index[target]
+	if !ok {
+		b.setErr(fmt.Errorf("tag %q: %w: %s", t.Name, ErrUnknownObject, target))
+
+		return ""
+	}
+
+	return b.add(object.TagType, object.EncodeTag(object.Tag{
+		Object:  target,
+		Type:    b.objects[i].typ,
+		Name:    t.Name,
+		Tagger:  object.Signature(orDefault(t.Tagger, defaultSignature)),
+		Message: t.Message,
+	}))
+}
+
+// Ref sets the reference name (e.g. "refs/heads/master") to point at hash.
+// The name must be HEAD or a valid name under refs/, see git
+// check-ref-format. The hash is written in lowercase.
+func (b *Builder) Ref(name, hash string) {
+	err := checkRefName(name)
+	if err != nil {
+		b.setErr(err)
+
+		return
+	}
+
+	hash, err = b.normalizeHash(hash)
+	if err != nil {
+		b.setErr(fmt.Errorf("%w %q: %w", ErrInvalidRef, name, err))
+
+		return
+	}
+
+	delete(b.symbolicRefs, name)
+	b.refs[name] = hash
+}
+
+// SymbolicRef sets the reference name to point at the reference target.
+// HEAD defaults to a symbolic reference to refs/heads/master. Both names are
+// validated as by Ref.
+func (b *Builder) SymbolicRef(name, target string) {
+	for _, n := range []string{name, target} {
+		err := checkRefName(n)
+		if err != nil {
+			b.setErr(err)
+
+			return
+		}
+	}
+
+	delete(b.refs, name)
+	b.symbolicRefs[name] = target
+}
+
+// normalizeHash validates the hex-encoded hash h and returns it in
+// lowercase.
+func (b *Builder) normalizeHash(h string) (string, error) {
+	_, err := object.DecodeHash(b.objectFormat, h)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(h), nil
+}
+
+// Build writes the repository into a new filesystem created according to
+// opts, with loose objects, references and a config file.
+func (b *Builder) Build(opts ...Option) (billy.Filesystem, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	o := newOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	fs, err := o.fsFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range []string{"objects/info", "objects/pack", "refs/heads", "refs/tags"} {
+		err = fs.MkdirAll(dir, 0o755)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = util.WriteFile(fs, "config", []byte(b.config()), 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, obj := range b.objects {
+		err = writeLooseObject(fs, obj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = b.writeRefs(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs, nil
+}
+
+func (b *Builder) add(t object.Type, content []byte) string {
+	h, err := object.Hash(b.objectFormat, t, content)
+	if err != nil {
+		b.setErr(err)
+
+		return ""
+	}
+
+	if _, ok := b.index[h]; !ok {
+		b.index[h] = len(b.objects)
+		b.objects = append(b.objects, builderObject{typ: t, hash: h, content: content})
+	}
+
+	return h
+}
+
+func (b *Builder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *Builder) config() string {
+	return repositoryConfig(b.objectFormat, false)
+}
+
+// repositoryConfig returns the config file of a repository using
+// objectFormat.
+func repositoryConfig(objectFormat string, bare bool) string {
+	var sb strings.Builder
+
+	version := 0
+	if objectFormat != objectFormatSHA1 {
+		version = 1
+	}
+
+	fmt.Fprintf(&sb, "[core]\n"+
+		"\trepositoryformatversion = %d\n"+
+		"\tfilemode = true\n"+
+		"\tbare = %t\n", version, bare)
+
+	if objectFormat != objectFormatSHA1 {
+		fmt.Fprintf(&sb, "[extensions]\n\tobjectformat = %s\n", objectFormat)
+	}
+
+	return sb.String()
+}
+
+func (b *Builder) writeRefs(fs billy.Filesystem) error {
+	refs := maps.Clone(b.refs)
+	symbolic := maps.Clone(b.symbolicRefs)
+
+	if _, ok := refs["HEAD"]; !ok {
+		if _, ok := symbolic["HEAD"]; !ok {
+			symbolic["HEAD"] = "refs/heads/master"
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(refs)) {
+		err := writeRef(fs, name, refs[name]+"\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(symbolic)) {
+		err := writeRef(fs, name, "ref: "+symbolic[name]+"\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeRef(fs billy.Filesystem, name, content string) error {
+	err := checkRefName(name)
+	if err != nil {
+		return err
+	}
+
+	err = fs.MkdirAll(path.Dir(name), 0o755)
+	if err != nil {
+		return err
+	}
+
+	return util.WriteFile(fs, name, []byte(content), 0o644)
+}
+
+// checkRefName reports whether name is HEAD or a reference under refs/ that
+// git accepts, following the rules of git check-ref-format: no empty
+// component or component starting with a dot or ending with .lock, no "..",
+// "@{", control character, space or any of ~^:?*[\, and no trailing dot.
+func checkRefName(name string) error {
+	if name == "HEAD" {
+		return nil
+	}
+
+	invalid := !strings.HasPrefix(name, "refs/") ||
+		strings.HasSuffix(name, ".") ||
+		strings.Contains(name, "..") ||
+		strings.Contains(name, "@{") ||
+		strings.ContainsFunc(name, func(r rune) bool {
+			return r < 0x20 || r == 0x7f || strings.ContainsRune(" ~^:?*[\\", r)
+		})
+
+	for component := range strings.SplitSeq(name, "/") {
+		if component == "" || strings.HasPrefix(component, ".") || strings.HasSuffix(component, ".lock") {
+			invalid = true
+		}
+	}
+
+	if invalid {
+		return fmt.Errorf("%w: %q", ErrInvalidRef, name)
+	}
+
+	return nil
+}
+
+// checkTreeEntryName reports whether name can be the name of a tree entry:
+// git rejects empty names, "." and "..", and names containing a slash or a
+// NUL byte, which would end the name in the tree encoding.
+func checkTreeEntryName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\x00") {
+		return fmt.Errorf("%w: name %q", ErrInvalidTreeEntry, name)
+	}
+
+	return nil
+}
+
+func writeLooseObject(fs billy.Filesystem, obj builderObject) error {
+	var buf bytes.Buffer
+
+	zw := zlib.NewWriter(&buf)
+
+	_, err := zw.Write(object.Header(obj.typ, len(obj.content)))
+	if err != nil {
+		return err
+	}
+
+	_, err = zw.Write(obj.content)
+	if err != nil {
+		return err
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return err
+	}
+
+	dir := path.Join("objects", obj.hash[:2])
+
+	err = fs.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	return util.WriteFile(fs, path.Join(dir, obj.hash[2:]), buf.Bytes(), 0o644)
+}
+
+func orDefault(s, def Signature) Signature {
+	if s == (Signature{}) {
+		return def
+	}
+
+	return s
+}
@@ -0,0 +1,206 @@
+package fixtures
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/go-git/go-billy/v6/memfs"
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git-fixtures/v6/internal/idxfile"
+	"github.com/go-git/go-git-fixtures/v6/internal/packfile"
+	"github.com/go-git/go-git-fixtures/v6/internal/revfile"
+)
+
+// DeltaStrategy selects how Builder.Packfile deltifies objects.
+type DeltaStrategy int
+
+const (
+	// DeltaNone stores every object in full.
+	DeltaNone DeltaStrategy = iota
+	// DeltaOFS stores deltas referencing their base by pack offset.
+	DeltaOFS
+	// DeltaREF stores deltas referencing their base by object hash.
+	DeltaREF
+)
+
+type PackOption func(*packOptions)
+
+type packOptions struct {
+	delta                DeltaStrategy
+	maxDepth             int
+	deltaBeforeBase      bool
+	largeOffsetThreshold int64
+}
+
+// WithDelta sets the delta strategy used by Builder.Packfile. Each object is
+// deltified against the previously added object of the same type.
+func WithDelta(s DeltaStrategy) PackOption {
+	return func(o *packOptions) {
+		o.delta = s
+	}
+}
+
+// WithDeltaDepth limits delta chains to depth deltas. Once a chain reaches
+// that depth, the next object of the same type is stored in full and starts
+// a new chain. Defaults to 50, like git. A depth of 0 stores every object in
+// full, while a negative depth makes Builder.Packfile fail.
+func WithDeltaDepth(depth int) PackOption {
+	return func(o *packOptions) {
+		o.maxDepth = depth
+	}
+}
+
+// WithDeltaBeforeBase writes every delta ahead of its base object. It can
+// only be combined with DeltaREF.
+func WithDeltaBeforeBase() PackOption {
+	return func(o *packOptions) {
+		o.deltaBeforeBase = true
+	}
+}
+
+// WithLargeOffsetThreshold writes the offsets of threshold and above to the
+// large offset table of the idx file, rather than only those past 2 GiB, so
+// that small packs exercise that table. The threshold must be between 1 and
+// 2 GiB, otherwise Builder.Packfile fails.
+func WithLargeOffsetThreshold(threshold int64) PackOption {
+	return func(o *packOptions) {
+		o.largeOffsetThreshold = threshold
+	}
+}
+
+// Packfile writes all objects added to the Builder into a version 2 packfile
+// along with its version 2 idx and version 1 rev files. Objects are written in
+// the order they were added, or in reverse order with WithDeltaBeforeBase.
+// The output is deterministic for a given input.
+//
+// Offsets past 2 GiB are written to the large offset table of the idx file.
+// Packs that large are impractical to build in tests, so
+// WithLargeOffsetThreshold lowers that limit instead.
+//
+// The returned Fixture serves the generated files from Packfile, Idx and
+// Rev, and has its PackfileHash, ObjectsCount, Head, Entries and
+// ScannerEntries filled in.
+func (b *Builder) Packfile(opts ...PackOption) (*Fixture, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	o := &packOptions{
+		delta:                DeltaNone,
+		maxDepth:             packfile.DefaultMaxDepth,
+		deltaBeforeBase:      false,
+		largeOffsetThreshold: idxfile.LargeOffsetThreshold,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var size int64
+
+	objects := make([]packfile.Object, 0, len(b.objects))
+	for _, obj := range b.objects {
+		objects = append(objects, packfile.Object{Type: obj.typ, Hash: obj.hash, Content: obj.content})
+		size += int64(len(obj.content))
+	}
+
+	pack, err := packfile.Encode(b.objectFormat, objects, packfile.WriterOptions{
+		Delta:           packfile.DeltaStrategy(o.delta),
+		MaxDepth:        o.maxDepth,
+		DeltaBeforeBase: o.deltaBeforeBase,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idxEntries := make([]idxfile.Entry, 0, len(pack.Entries))
+	revEntries := make([]revfile.Entry, 0, len(pack.Entries))
+
+	for _, e := range pack.Entries {
+		idxEntries = append(idxEntries, idxfile.Entry{Hash: e.Hash, Offset: e.Offset, CRC32: e.CRC32})
+		revEntries = append(revEntries, revfile.Entry{Hash: e.Hash, Offset: e.Offset})
+	}
+
+	idx, err := idxfile.EncodeWithThreshold(b.objectFormat, idxEntries, pack.Checksum, o.largeOffsetThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := revfile.Encode(b.objectFormat, revEntries, pack.Checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hex.EncodeToString(pack.Checksum)
+	fs := memfs.New()
+
+	for ext, content := range map[string][]byte{"pack": pack.Data, "idx": idx, "rev": rev} {
+		err = util.WriteFile(fs, fmt.Sprintf("data/pack-%s.%s", hash, ext), content, 0o644)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Fixture{
+		Name:         "generated-" + hash[:12],
+		URL:          "",
+		Tags:         o.tags(),
+		Head:         b.head(),
+		PackfileHash: hash,
+		DotGitHash:   "",
+		WorktreeHash: "",
+		ObjectsCount: int32(len(pack.Entries)), //nolint:gosec // bounded by the pack header.
+		ObjectFormat: b.objectFormat,
+		fs:           fs,
+		objectBytes:  size,
+
+		largeOffsetThreshold: o.largeOffsetThreshold,
+	}, nil
+}
+
+// head returns the commit HEAD resolves to, or an empty string.
+func (b *Builder) head() string {
+	name := "HEAD"
+	for range len(b.symbolicRefs) + 2 {
+		if h, ok := b.refs[name]; ok {
+			return h
+		}
+
+		target, ok := b.symbolicRefs[name]
+		if !ok {
+			if name == "HEAD" {
+				target = "refs/heads/master"
+			} else {
+				return ""
+			}
+		}
+
+		name = target
+	}
+
+	return ""
+}
+
+func (o *packOptions) tags() []Tag {
+	tags := []Tag{
+		TagPackfile, TagPackV2, TagIDXV2, TagRevV1,
+		TagPackfileEntries, TagScannerEntries, TagGenerated,
+	}
+
+	if o.maxDepth == 0 {
+		return tags
+	}
+
+	switch o.delta {
+	case DeltaOFS:
+		tags = append(tags, TagOFSDelta)
+	case DeltaREF:
+		tags = append(tags, TagREFDelta)
+	case DeltaNone:
+	}
+
+	if o.deltaBeforeBase {
+		tags = append(tags, TagDeltaBeforeBase)
+	}
+
+	return tags
+}
@@ -0,0 +1,204 @@
+package fixtures_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/go-git/go-git-fixtures/v6/internal/idxfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHistoryBuilder(t *testing.T, format string, commits int) *fixtures.Builder {
+	t.Helper()
+
+	b := fixtures.NewBuilder(format)
+
+	var (
+		parents []string
+		content []byte
+	)
+
+	for i := range commits {
+		content = fmt.Appendf(content, "line %d of a file long enough to be deltified\n", i)
+		blob := b.Blob(content)
+		tree := b.Tree(fixtures.TreeEntry{Name: "file", Mode: fixtures.ModeRegular, Hash: blob})
+		commit := b.Commit(fixtures.CommitSpec{Tree: tree, Parents: parents, Message: fmt.Sprintf("commit %d\n", i)})
+		parents = []string{commit}
+	}
+
+	b.Ref("refs/heads/master", parents[0])
+
+	return b
+}
+
+func TestBuilderPackfile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		opts      []fixtures.PackOption
+		deltaType int
+		tag       fixtures.Tag
+	}{
+		{name: "none", opts: nil, deltaType: 0, tag: "packfile"},
+		{name: "ofs-delta", opts: []fixtures.PackOption{fixtures.WithDelta(fixtures.DeltaOFS)}, deltaType: 6, tag: "ofs-delta"},
+		{name: "ref-delta", opts: []fixtures.PackOption{fixtures.WithDelta(fixtures.DeltaREF)}, deltaType: 7, tag: "ref-delta"},
+		{
+			name:      "delta-before-base",
+			opts:      []fixtures.PackOption{fixtures.WithDelta(fixtures.DeltaREF), fixtures.WithDeltaBeforeBase()},
+			deltaType: 7,
+			tag:       "delta-before-base",
+		},
+	}
+
+	for _, format := range []string{"sha1", "sha256"} {
+		for _, tc := range tests {
+			t.Run(format+"/"+tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				b := newHistoryBuilder(t, format, 5)
+
+				f, err := b.Packfile(tc.opts...)
+				require.NoError(t, err)
+
+				assert.True(t, f.Is(tc.tag))
+				assert.Equal(t, format, f.ObjectFormat)
+				assert.EqualValues(t, 15, f.ObjectsCount)
+				assert.NotEmpty(t, f.Head)
+				assert.Len(t, f.Entries(), 15)
+
+				entries := f.ScannerEntries()
+				require.Len(t, entries, 15)
+
+				var deltas int
+
+				for _, e := range entries {
+					if e.Type == 6 || e.Type == 7 {
+						assert.Equal(t, tc.deltaType, e.Type)
+						assert.Empty(t, e.Hash)
+
+						deltas++
+					}
+				}
+
+				if tc.deltaType != 0 {
+					assert.Equal(t, 12, deltas)
+				} else {
+					assert.Zero(t, deltas)
+				}
+
+				for _, open := range []func() (io.ReadCloser, error){
+					func() (io.ReadCloser, error) { return f.Packfile() },
+					func() (io.ReadCloser, error) { return f.Idx() },
+					func() (io.ReadCloser, error) { return f.Rev() },
+				} {
+					file, err := open()
+					require.NoError(t, err)
+
+					content, err := io.ReadAll(file)
+					require.NoError(t, err)
+					assert.NotEmpty(t, content)
+					require.NoError(t, file.Close())
+				}
+
+				again, err := newHistoryBuilder(t, format, 5).Packfile(tc.opts...)
+				require.NoError(t, err)
+				assert.Equal(t, f.PackfileHash, again.PackfileHash, "output must be deterministic")
+			})
+		}
+	}
+}
+
+func TestBuilderPackfileDeltaDepth(t *testing.T) {
+	t.Parallel()
+
+	f, err := newHistoryBuilder(t, "sha1", 7).Packfile(
+		fixtures.WithDelta(fixtures.DeltaOFS), fixtures.WithDeltaDepth(2))
+	require.NoError(t, err)
+
+	depth := map[int64]int{}
+	for _, e := range f.ScannerEntries() {
+		if e.Type == 6 {
+			depth[e.Offset] = depth[e.OffsetReference] + 1
+			assert.LessOrEqual(t, depth[e.Offset], 2)
+		}
+	}
+
+	assert.NotEmpty(t, depth)
+}
+
+func TestBuilderPackfileNoDeltaDepth(t *testing.T) {
+	t.Parallel()
+
+	f, err := newHistoryBuilder(t, "sha1", 7).Packfile(
+		fixtures.WithDelta(fixtures.DeltaOFS), fixtures.WithDeltaDepth(0))
+	require.NoError(t, err)
+
+	for _, e := range f.ScannerEntries() {
+		assert.NotEqual(t, 6, e.Type, "delta at offset %d", e.Offset)
+	}
+
+	assert.False(t, f.Is(fixtures.TagOFSDelta))
+
+	_, err = newHistoryBuilder(t, "sha1", 2).Packfile(
+		fixtures.WithDelta(fixtures.DeltaOFS), fixtures.WithDeltaDepth(-1))
+	require.Error(t, err)
+}
+
+func TestBuilderPackfileLargeOffsetThreshold(t *testing.T) {
+	t.Parallel()
+
+	b := newHistoryBuilder(t, "sha1", 5)
+
+	f, err := b.Packfile()
+	require.NoError(t, err)
+
+	large, err := b.Packfile(fixtures.WithLargeOffsetThreshold(1))
+	require.NoError(t, err)
+	require.NoError(t, large.Verify())
+
+	assert.Equal(t, f.PackfileHash, large.PackfileHash)
+
+	idx := readIdx(t, large)
+
+	decoded, err := idxfile.Decode("sha1", idx)
+	require.NoError(t, err)
+	require.Len(t, decoded.Entries, int(f.ObjectsCount))
+
+	for _, e := range decoded.Entries {
+		assert.Equal(t, f.Entries()[e.Hash], e.Offset, e.Hash)
+	}
+
+	// Every offset moves to the large offset table, 8 bytes each.
+	assert.Len(t, idx, len(readIdx(t, f))+8*int(f.ObjectsCount))
+
+	for _, threshold := range []int64{0, 1<<31 + 1} {
+		_, err = b.Packfile(fixtures.WithLargeOffsetThreshold(threshold))
+		require.ErrorIs(t, err, idxfile.ErrInvalidThreshold)
+	}
+}
+
+func readIdx(t *testing.T, f *fixtures.Fixture) []byte {
+	t.Helper()
+
+	file, err := f.Idx()
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+
+	return content
+}
+
+func TestBuilderPackfileDeltaBeforeBaseRequiresRefDelta(t *testing.T) {
+	t.Parallel()
+
+	_, err := newHistoryBuilder(t, "sha1", 2).Packfile(
+		fixtures.WithDelta(fixtures.DeltaOFS), fixtures.WithDeltaBeforeBase())
+	require.Error(t, err)
+}
@@ -0,0 +1,220 @@
+package fixtures_test
+
+import (
+	"compress/zlib"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v6/util"
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilderKnownHashes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format    string
+		emptyTree string
+		blob      string
+	}{
+		{
+			format:    "sha1",
+			emptyTree: "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+			blob:      "ce013625030ba8dba906f756967f9e9ca394464a",
+		},
+		{
+			format:    "sha256",
+			emptyTree: "6ef19b41225c5369f1c104d45d8d85efa9b057b53b14b4b9b939dd74decc5321",
+			blob:      "2cf8d83d9ee29543b34a87727421fdecb7e3f3a183d337639025de576db9ebb4",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.format, func(t *testing.T) {
+			t.Parallel()
+
+			b := fixtures.NewBuilder(tc.format)
+			assert.Equal(t, tc.emptyTree, b.Tree())
+			assert.Equal(t, tc.blob, b.Blob([]byte("hello\n")))
+		})
+	}
+}
+
+func TestBuilderBuild(t *testing.T) {
+	t.Parallel()
+
+	b := fixtures.NewBuilder("sha1")
+	blob := b.Blob([]byte("hello\n"))
+	sub := b.Tree(fixtures.TreeEntry{Name: "file", Mode: fixtures.ModeRegular, Hash: blob})
+	tree := b.Tree(
+		fixtures.TreeEntry{Name: "z", Mode: fixtures.ModeRegular, Hash: blob},
+		fixtures.TreeEntry{Name: "dir", Mode: fixtures.ModeDir, Hash: sub},
+	)
+	commit := b.Commit(fixtures.CommitSpec{
+		Tree: tree,
+		Author: fixtures.Signature{
+			Name:  "John Doe",
+			Email: "john@example.com",
+			When:  time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600)),
+		},
+		Message: "initial\n",
+	})
+	tag := b.Tag(fixtures.TagSpec{Name: "v1.0.0", Target: commit, Message: "v1.0.0\n"})
+
+	b.Ref("refs/heads/master", commit)
+	b.Ref("refs/tags/v1.0.0", tag)
+
+	fs, err := b.Build(fixtures.WithTargetDir(t.TempDir))
+	require.NoError(t, err)
+
+	head, err := util.ReadFile(fs, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "ref: refs/heads/master\n", string(head))
+
+	master, err := util.ReadFile(fs, "refs/heads/master")
+	require.NoError(t, err)
+	assert.Equal(t, commit+"\n", string(master))
+
+	f, err := fs.Open("objects/" + commit[:2] + "/" + commit[2:])
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+
+	zr, err := zlib.NewReader(f)
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, "commit 162\x00tree "+tree+"\n"+
+		"author John Doe <john@example.com> 1577930645 +0100\n"+
+		"committer John Doe <john@example.com> 1577930645 +0100\n"+
+		"\ninitial\n", string(content))
+
+	_, err = fs.Stat("config")
+	require.NoError(t, err)
+}
+
+func TestBuilderErrors(t *testing.T) {
+	t.Parallel()
+
+	b := fixtures.NewBuilder("sha1")
+	b.Tag(fixtures.TagSpec{Name: "v1", Target: "4b825dc642cb6eb9a060e54bf8d69288fbee4904"})
+
+	_, err := b.Build()
+	require.ErrorIs(t, err, fixtures.ErrUnknownObject)
+
+	b = fixtures.NewBuilder("md5")
+	b.Blob(nil)
+
+	_, err = b.Build()
+	require.Error(t, err)
+
+	b = fixtures.NewBuilder("sha1")
+	b.Ref("refs/heads/master", "not-a-hash")
+
+	_, err = b.Build()
+	require.ErrorIs(t, err, fixtures.ErrInvalidRef)
+}
+
+func TestBuilderInvalidTreeEntries(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string][]string{
+		"empty":     {""},
+		"dot":       {"."},
+		"dot-dot":   {".."},
+		"slash":     {"a/b"},
+		"nul":       {"a\x00b"},
+		"duplicate": {"a", "a"},
+	}
+
+	for name, entries := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b := fixtures.NewBuilder("sha1")
+			blob := b.Blob(nil)
+
+			te := make([]fixtures.TreeEntry, 0, len(entries))
+			for _, e := range entries {
+				te = append(te, fixtures.TreeEntry{Name: e, Mode: fixtures.ModeRegular, Hash: blob})
+			}
+
+			assert.Empty(t, b.Tree(te...))
+
+			_, err := b.Build()
+			require.ErrorIs(t, err, fixtures.ErrInvalidTreeEntry)
+		})
+	}
+}
+
+func TestBuilderInvalidRefNames(t *testing.T) {
+	t.Parallel()
+
+	names := []string{
+		"master",
+		"refs/../../config",
+		"refs/heads/a..b",
+		"refs/heads/x/",
+		"refs/heads//x",
+		"refs/heads/.hidden",
+		"refs/heads/x.lock",
+		"refs/heads/x.",
+		"refs/heads/a\x01",
+		"refs/heads/a b",
+		"refs/heads/a~1",
+		"refs/heads/a@{1}",
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b := fixtures.NewBuilder("sha1")
+			b.Ref(name, b.Commit(fixtures.CommitSpec{Tree: b.Tree(), Message: "initial\n"}))
+
+			_, err := b.Build()
+			require.ErrorIs(t, err, fixtures.ErrInvalidRef)
+
+			b = fixtures.NewBuilder("sha1")
+			b.SymbolicRef("HEAD", name)
+
+			_, err = b.Build()
+			require.ErrorIs(t, err, fixtures.ErrInvalidRef)
+		})
+	}
+}
+
+func TestBuilderLowercasesHashes(t *testing.T) {
+	t.Parallel()
+
+	b := fixtures.NewBuilder("sha1")
+	tree := b.Tree()
+	parent := b.Commit(fixtures.CommitSpec{Tree: tree, Message: "initial\n"})
+	commit := b.Commit(fixtures.CommitSpec{
+		Tree:    strings.ToUpper(tree),
+		Parents: []string{strings.ToUpper(parent)},
+		Message: "second\n",
+	})
+	b.Ref("refs/heads/master", strings.ToUpper(commit))
+	tag := b.Tag(fixtures.TagSpec{Name: "v1", Target: strings.ToUpper(commit), Message: "v1\n"})
+
+	want := fixtures.NewBuilder("sha1")
+	wantCommit := want.Commit(fixtures.CommitSpec{
+		Tree:    want.Tree(),
+		Parents: []string{parent},
+		Message: "second\n",
+	})
+	assert.Equal(t, wantCommit, commit)
+	assert.Equal(t, want.Tag(fixtures.TagSpec{Name: "v1", Target: wantCommit, Message: "v1\n"}), tag)
+
+	fs, err := b.Build()
+	require.NoError(t, err)
+
+	master, err := util.ReadFile(fs, "refs/heads/master")
+	require.NoError(t, err)
+	assert.Equal(t, commit+"\n", string(master))
+}
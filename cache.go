@@ -0,0 +1,123 @@
+package fixtures
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/memfs"
+	"github.com/go-git/go-git-fixtures/v6/internal/tgz"
+)
+
+// archiveCache holds the .git and worktree archives read so far, keyed by
+// path. Archives are read-only once cached, and shared by every caller.
+//
+//nolint:gochecknoglobals
+var archiveCache = newMetadataCache[*cachedArchive]()
+
+// stampCache holds the cache directory stamps of the archives, keyed by path.
+//
+//nolint:gochecknoglobals
+var stampCache = newMetadataCache[string]()
+
+// cacheDirVersion is the version of the layout of cache directory entries,
+// to be bumped whenever extraction changes how they are laid out.
+const cacheDirVersion = 2
+
+// metadataCache memoizes values derived from fixture data files. Data files
+// are named after their content hash, so the file name is used as key and
+// values are computed at most once per process.
+type metadataCache[T any] struct {
+	mu     sync.Mutex
+	values map[string]*cachedValue[T]
+}
+
+type cachedValue[T any] struct {
+	once  sync.Once
+	value T
+	err   error
+}
+
+func newMetadataCache[T any]() *metadataCache[T] {
+	return &metadataCache[T]{
+		mu:     sync.Mutex{},
+		values: map[string]*cachedValue[T]{},
+	}
+}
+
+// get returns the value cached for key, calling compute to fill it in on
+// first use. Concurrent callers for the same key wait for a single compute.
+func (c *metadataCache[T]) get(key string, compute func() (T, error)) (T, error) {
+	c.mu.Lock()
+
+	v, ok := c.values[key]
+	if !ok {
+		v = &cachedValue[T]{}
+		c.values[key] = v
+	}
+
+	c.mu.Unlock()
+
+	v.once.Do(func() {
+		v.value, v.err = compute()
+	})
+
+	return v.value, v.err
+}
+
+// cachedArchive is a decompressed fixture archive.
+type cachedArchive struct {
+	// tar is the uncompressed tarball, to be extracted into filesystems
+	// holding a full copy of the archive.
+	tar []byte
+	// base is the archive extracted into memfs, used as the read-only base
+	// of overlays.
+	base billy.Filesystem
+}
+
+func loadArchive(src billy.Filesystem, name string) (*cachedArchive, error) {
+	file, err := src.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := tgz.Decompress(file)
+	if err != nil {
+		return nil, err
+	}
+
+	base := memfs.New()
+
+	err = tgz.ExtractTar(bytes.NewReader(data), base)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cachedArchive{tar: data, base: base}, nil
+}
+
+// archiveStamp returns the cache directory stamp of the archive name, made of
+// the layout version and a digest of the archive.
+func archiveStamp(src billy.Filesystem, name string) (string, error) {
+	return stampCache.get(name, func() (string, error) {
+		file, err := src.Open(name)
+		if err != nil {
+			return "", err
+		}
+
+		defer file.Close()
+
+		h := sha256.New()
+
+		_, err = io.Copy(h, file)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("v%d sha256:%s", cacheDirVersion, hex.EncodeToString(h.Sum(nil))), nil
+	})
+}
@@ -0,0 +1,315 @@
+package fixtures
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/dotgit"
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+)
+
+//nolint:gochecknoglobals
+var commitsCache = newMetadataCache[Commits]()
+
+// Commit is the expected commit graph metadata of a single commit of a
+// fixture. Fields use plain types so go-git-fixtures does not depend on
+// go-git.
+type Commit struct {
+	// Hash is the hex-encoded commit hash.
+	Hash string
+	// Tree is the hex-encoded hash of the commit's root tree.
+	Tree string
+	// Parents are the hex-encoded parent hashes, in commit order.
+	Parents []string
+	// Generation is the topological level of the commit, as stored in
+	// commit-graph files: 1 for root commits, otherwise one more than the
+	// highest generation of its parents.
+	Generation int
+	// CommitterDate is the committer timestamp, in the committer's
+	// timezone.
+	CommitterDate time.Time
+}
+
+// Commits is the set of commits of a fixture, ordered by hash.
+type Commits []Commit
+
+// Commits returns the commit graph metadata of every commit of the fixture,
+// ordered by hash. Commits are read from the fixture's packfile, or from its
+// .git directory if it has no packfile.
+//
+// The commits are computed on first use and cached. Returns nil if the
+// fixture has no commits or they cannot be read (e.g. thin packs).
+func (f *Fixture) Commits() Commits {
+	key, load := f.commitSource()
+	if load == nil {
+		return nil
+	}
+
+	commits, err := commitsCache.get(key, func() (Commits, error) {
+		contents, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		return newCommits(contents)
+	})
+	if err != nil || len(commits) == 0 {
+		return nil
+	}
+
+	out := make(Commits, len(commits))
+	for i, c := range commits {
+		c.Parents = slices.Clone(c.Parents)
+		out[i] = c
+	}
+
+	return out
+}
+
+// Get returns the commit with the given hash.
+func (c Commits) Get(hash string) (Commit, bool) {
+	i, ok := c.index(hash)
+	if !ok {
+		return Commit{}, false
+	}
+
+	return c[i], true
+}
+
+// TopoOrder returns the commit hashes in topological order, as in git log
+// --topo-order: no commit is listed before all of its children, and the line
+// of the last parent of a merge is listed before those of its other parents.
+// Tips are visited newest first, and ties are broken by hash.
+func (c Commits) TopoOrder() []string {
+	children, tips := c.walkState()
+
+	order := make([]string, 0, len(c))
+	stack := slices.Clone(tips)
+	slices.Reverse(stack)
+
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		order = append(order, c[i].Hash)
+
+		for _, parent := range c[i].Parents {
+			p, ok := c.index(parent)
+			if !ok {
+				continue
+			}
+
+			children[p]--
+			if children[p] == 0 {
+				stack = append(stack, p)
+			}
+		}
+	}
+
+	return order
+}
+
+// DateOrder returns the commit hashes ordered by committer date, newest
+// first, without listing any commit before all of its children, as in git
+// log --date-order. Ties are broken by hash.
+func (c Commits) DateOrder() []string {
+	children, tips := c.walkState()
+
+	order := make([]string, 0, len(c))
+	queue := &commitQueue{commits: c, items: tips}
+	heap.Init(queue)
+
+	for queue.Len() > 0 {
+		i, _ := heap.Pop(queue).(int)
+		order = append(order, c[i].Hash)
+
+		for _, parent := range c[i].Parents {
+			p, ok := c.index(parent)
+			if !ok {
+				continue
+			}
+
+			children[p]--
+			if children[p] == 0 {
+				heap.Push(queue, p)
+			}
+		}
+	}
+
+	return order
+}
+
+// walkState returns the number of children of every commit, and the indices
+// of the commits without children ordered newest first.
+func (c Commits) walkState() ([]int, []int) {
+	children := make([]int, len(c))
+
+	for _, commit := range c {
+		for _, parent := range commit.Parents {
+			if p, ok := c.index(parent); ok {
+				children[p]++
+			}
+		}
+	}
+
+	var tips []int
+
+	for i := range c {
+		if children[i] == 0 {
+			tips = append(tips, i)
+		}
+	}
+
+	slices.SortFunc(tips, c.newerFirst)
+
+	return children, tips
+}
+
+func (c Commits) index(hash string) (int, bool) {
+	return slices.BinarySearchFunc(c, hash, func(c Commit, h string) int {
+		return strings.Compare(c.Hash, h)
+	})
+}
+
+func (c Commits) newerFirst(i, j int) int {
+	if cmp := c[j].CommitterDate.Compare(c[i].CommitterDate); cmp != 0 {
+		return cmp
+	}
+
+	return strings.Compare(c[i].Hash, c[j].Hash)
+}
+
+// commitQueue is a heap of commit indices, newest first.
+type commitQueue struct {
+	commits Commits
+	items   []int
+}
+
+func (q *commitQueue) Len() int           { return len(q.items) }
+func (q *commitQueue) Less(i, j int) bool { return q.commits.newerFirst(q.items[i], q.items[j]) < 0 }
+func (q *commitQueue) Swap(i, j int)      { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *commitQueue) Push(x any) {
+	i, _ := x.(int)
+	q.items = append(q.items, i)
+}
+
+func (q *commitQueue) Pop() any {
+	n := len(q.items) - 1
+	x := q.items[n]
+	q.items = q.items[:n]
+
+	return x
+}
+
+// newCommits decodes the given commit contents, keyed by hash, and computes
+// their generation numbers. Parents missing from contents, as in shallow
+// repositories, do not contribute to generation numbers.
+func newCommits(contents map[string][]byte) (Commits, error) {
+	commits := make(Commits, 0, len(contents))
+
+	for hash, content := range contents {
+		decoded, err := object.DecodeCommit(content)
+		if err != nil {
+			return nil, fmt.Errorf("commit %s: %w", hash, err)
+		}
+
+		commits = append(commits, Commit{
+			Hash:          hash,
+			Tree:          decoded.Tree,
+			Parents:       decoded.Parents,
+			Generation:    0,
+			CommitterDate: decoded.Committer.When,
+		})
+	}
+
+	slices.SortFunc(commits, func(a, b Commit) int {
+		return strings.Compare(a.Hash, b.Hash)
+	})
+
+	// Walking parents before children yields each generation once all of
+	// its parents are known.
+	order := commits.TopoOrder()
+	for i := len(order) - 1; i >= 0; i-- {
+		idx, _ := commits.index(order[i])
+
+		generation := 1
+		for _, parent := range commits[idx].Parents {
+			if p, ok := commits.index(parent); ok {
+				generation = max(generation, commits[p].Generation+1)
+			}
+		}
+
+		commits[idx].Generation = generation
+	}
+
+	return commits, nil
+}
+
+// commitSource returns the cache key and loader of the raw commits of the
+// fixture, preferring its packfile over its .git directory. The loader is nil
+// if the fixture has neither.
+func (f *Fixture) commitSource() (string, func() (map[string][]byte, error)) {
+	if f.PackfileHash != "" {
+		return f.packfilePath("pack"), f.packfileCommits
+	}
+
+	if name := f.dotGitArchive(); name != "" {
+		return name, f.dotGitCommits
+	}
+
+	return "", nil
+}
+
+func (f *Fixture) packfileCommits() (map[string][]byte, error) {
+	objects, err := f.objects()
+	if err != nil {
+		return nil, err
+	}
+
+	contents := map[string][]byte{}
+
+	for _, o := range objects {
+		if o.Type == int(object.CommitType) {
+			contents[o.Hash] = o.content
+		}
+	}
+
+	return contents, nil
+}
+
+func (f *Fixture) dotGitCommits() (map[string][]byte, error) {
+	fs, err := f.DotGit()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := dotgit.ReadConfig(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	store := dotgit.NewObjectStore(fs, cfg.ObjectFormat)
+
+	hashes, err := store.Hashes()
+	if err != nil {
+		return nil, err
+	}
+
+	contents := map[string][]byte{}
+
+	for _, h := range hashes {
+		t, content, err := store.Get(h)
+		if err != nil {
+			return nil, err
+		}
+
+		if t == object.CommitType {
+			contents[h] = content
+		}
+	}
+
+	return contents, nil
+}
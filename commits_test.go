@@ -0,0 +1,304 @@
+package fixtures_test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-billy/v6/util"
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noCommits are the fixtures without commits to read: empty ones, thin packs
+// and worktrees without a .git directory.
+//
+//nolint:gochecknoglobals
+var noCommits = []string{"empty", "thinpack", "alternates", "dirty", "linked-worktree"}
+
+func TestCommits(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range fixtures.All() {
+		commits := f.Commits()
+		if slices.Contains(noCommits, f.Name) {
+			assert.Nil(t, commits, f.Name)
+
+			continue
+		}
+
+		require.NotNil(t, commits, f.Name)
+
+		assert.True(t, slices.IsSortedFunc(commits, func(a, b fixtures.Commit) int {
+			return strings.Compare(a.Hash, b.Hash)
+		}))
+
+		for _, c := range commits {
+			assert.NotEmpty(t, c.Tree)
+			assert.False(t, c.CommitterDate.IsZero())
+			assert.Positive(t, c.Generation, c.Hash)
+		}
+
+		assertChildrenFirst(t, commits, commits.TopoOrder())
+		assertChildrenFirst(t, commits, commits.DateOrder())
+	}
+}
+
+// TestCommitsGolden checks the orders against those of git rev-list --all
+// --topo-order and --date-order.
+func TestCommitsGolden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		topoOrder []string
+		dateOrder []string
+	}{
+		{
+			name: "basic-ofs-delta",
+			topoOrder: []string{
+				"6ecf0ef2c2dffb796033e5a02219af86ec6584e5",
+				"e8d3ffab552895c19b9fcf7aa264d277cde33881",
+				"918c48b83bd081e863dbe1b80f8998f058cd8294",
+				"af2d6a6954d532f8ffb47615169c8fdf9d383a1a",
+				"1669dce138d9b841a518c64b10914d88f5e488ea",
+				"a5b8b09e2f8fcb0bb99d3ccb0958157b40890d69",
+				"b8e471f58bcbca63b07bda20e428190409c2db47",
+				"35e85108805c84807bc66a02d91535e1e24b38b9",
+				"b029517f6300c2da0f4b651b8642506cd6aaf45d",
+			},
+			dateOrder: []string{
+				"6ecf0ef2c2dffb796033e5a02219af86ec6584e5",
+				"e8d3ffab552895c19b9fcf7aa264d277cde33881",
+				"918c48b83bd081e863dbe1b80f8998f058cd8294",
+				"af2d6a6954d532f8ffb47615169c8fdf9d383a1a",
+				"1669dce138d9b841a518c64b10914d88f5e488ea",
+				"a5b8b09e2f8fcb0bb99d3ccb0958157b40890d69",
+				"35e85108805c84807bc66a02d91535e1e24b38b9",
+				"b8e471f58bcbca63b07bda20e428190409c2db47",
+				"b029517f6300c2da0f4b651b8642506cd6aaf45d",
+			},
+		},
+		{
+			name: "merge-base",
+			topoOrder: []string{
+				"dce0e0c20d701c3d260146e443d6b3b079505191",
+				"25ca6c810c08482d61113fbcaaada38bb59093a8",
+				"628f1a42b70380ed05734bf01b468b46206ef1ea",
+				"8b72fabdc4222c3ff965bc310ded788c601c50ed",
+				"ff84393134864cf9d3a9853a81bde81778bd5805",
+				"d1b0093698e398d596ef94d646c4db37e8d1e970",
+				"806824d4778e94fe7c3244e92a9cd07090c9ab54",
+				"ccaaa99c21dad7e9f392c36ae8cb72dc63bed458",
+				"ac198ef5191568ee9ccd7523a464c0370ac7b33f",
+				"14777cf3e209334592fbfd0b878f6868394db836",
+				"265f6e1de21ebd5c85833c5db599200f6017cb41",
+				"4709e13a3cbb300c2b8a917effda776e1b8955c7",
+				"29740cfaf0c2ee4bb532dba9e80040ca738f367c",
+				"38468e274e91e50ffb637b88a1954ab6193fe974",
+				"2c84807970299ba98951c65fe81ebbaac01030f0",
+				"31a7e081a28f149ee98ffd13ba1a6d841a5f46fd",
+				"bb355b64e18386dbc3af63dfd09c015c44cbd9b6",
+				"8d08dd1388b82dd354cb43918d83da86c76b0978",
+				"f9ed2d26ce638fdab9270fd941bc2dfa901bfa62",
+				"336e6291f78890e8f8e5c832fed0110983559d16",
+				"d64b894762ab5f09e2b155221b90c18bd0637236",
+				"b6e1fc8dad4f1068fb42774ec5fc65c065b2c312",
+				"840a6877771ee57e504d2c74d34fd6bcf758ddf5",
+			},
+			dateOrder: []string{
+				"dce0e0c20d701c3d260146e443d6b3b079505191",
+				"25ca6c810c08482d61113fbcaaada38bb59093a8",
+				"d1b0093698e398d596ef94d646c4db37e8d1e970",
+				"806824d4778e94fe7c3244e92a9cd07090c9ab54",
+				"628f1a42b70380ed05734bf01b468b46206ef1ea",
+				"ccaaa99c21dad7e9f392c36ae8cb72dc63bed458",
+				"8b72fabdc4222c3ff965bc310ded788c601c50ed",
+				"ac198ef5191568ee9ccd7523a464c0370ac7b33f",
+				"14777cf3e209334592fbfd0b878f6868394db836",
+				"265f6e1de21ebd5c85833c5db599200f6017cb41",
+				"ff84393134864cf9d3a9853a81bde81778bd5805",
+				"4709e13a3cbb300c2b8a917effda776e1b8955c7",
+				"29740cfaf0c2ee4bb532dba9e80040ca738f367c",
+				"38468e274e91e50ffb637b88a1954ab6193fe974",
+				"2c84807970299ba98951c65fe81ebbaac01030f0",
+				"336e6291f78890e8f8e5c832fed0110983559d16",
+				"d64b894762ab5f09e2b155221b90c18bd0637236",
+				"b6e1fc8dad4f1068fb42774ec5fc65c065b2c312",
+				"840a6877771ee57e504d2c74d34fd6bcf758ddf5",
+				"31a7e081a28f149ee98ffd13ba1a6d841a5f46fd",
+				"bb355b64e18386dbc3af63dfd09c015c44cbd9b6",
+				"8d08dd1388b82dd354cb43918d83da86c76b0978",
+				"f9ed2d26ce638fdab9270fd941bc2dfa901bfa62",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			commits := fixtures.ByName(tc.name).Commits()
+			assert.Equal(t, tc.topoOrder, commits.TopoOrder())
+			assert.Equal(t, tc.dateOrder, commits.DateOrder())
+		})
+	}
+}
+
+// TestCommitsGenerationGolden checks generation numbers against the
+// topological levels git wrote in the commit-graph files of the fixtures.
+func TestCommitsGenerationGolden(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"commit-graph", "commit-graph-chain", "commit-graph-chain-2"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f := fixtures.ByName(name)
+			commits := f.Commits()
+
+			fs, err := f.DotGit()
+			require.NoError(t, err)
+
+			graphs := []string{"objects/info/commit-graph"}
+
+			chain, err := util.ReadFile(fs, "objects/info/commit-graphs/commit-graph-chain")
+			if err == nil {
+				graphs = nil
+				for _, h := range strings.Fields(string(chain)) {
+					graphs = append(graphs, "objects/info/commit-graphs/graph-"+h+".graph")
+				}
+			}
+
+			levels := map[string]int{}
+
+			for _, name := range graphs {
+				data, err := util.ReadFile(fs, name)
+				require.NoError(t, err)
+				maps.Copy(levels, readCommitGraphLevels(t, data))
+			}
+
+			require.NotEmpty(t, levels)
+
+			for hash, level := range levels {
+				c, ok := commits.Get(hash)
+				require.True(t, ok, hash)
+				assert.Equal(t, level, c.Generation, hash)
+			}
+		})
+	}
+}
+
+// readCommitGraphLevels returns the topological level of every commit of the
+// sha1 commit-graph file data, see gitformat-commit-graph(5).
+func readCommitGraphLevels(t *testing.T, data []byte) map[string]int {
+	t.Helper()
+
+	const (
+		hashSize  = 20
+		entrySize = hashSize + 16
+	)
+
+	require.Equal(t, "CGPH", string(data[:4]))
+
+	chunks := map[string][]byte{}
+	table := data[8:]
+
+	for i := range int(data[6]) {
+		entry := table[i*12:]
+		start := binary.BigEndian.Uint64(entry[4:])
+		end := binary.BigEndian.Uint64(entry[16:])
+		chunks[string(entry[:4])] = data[start:end]
+	}
+
+	lookup, cdat := chunks["OIDL"], chunks["CDAT"]
+	require.Len(t, cdat, len(lookup)/hashSize*entrySize)
+
+	levels := make(map[string]int, len(lookup)/hashSize)
+	for i := range len(lookup) / hashSize {
+		hash := hex.EncodeToString(lookup[i*hashSize : (i+1)*hashSize])
+		// The level is in the upper 30 bits following the tree and parents.
+		levels[hash] = int(binary.BigEndian.Uint32(cdat[i*entrySize+hashSize+8:]) >> 2)
+	}
+
+	return levels
+}
+
+func assertChildrenFirst(t *testing.T, commits fixtures.Commits, order []string) {
+	t.Helper()
+
+	require.Len(t, order, len(commits))
+
+	position := make(map[string]int, len(order))
+	for i, h := range order {
+		position[h] = i
+	}
+
+	for _, c := range commits {
+		for _, p := range c.Parents {
+			if pos, ok := position[p]; ok {
+				assert.Less(t, position[c.Hash], pos, "%s listed after its parent %s", c.Hash, p)
+			}
+		}
+	}
+}
+
+func TestCommitsCommitGraph(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByTag("commit-graph-chain-2").One()
+	commits := f.Commits()
+	require.Len(t, commits, 38)
+
+	head, ok := commits.Get(f.Head)
+	require.True(t, ok)
+	assert.Equal(t, 33, head.Generation)
+	assert.Equal(t, []string{
+		"3048d280d2d5b258d9e582a226ff4bbed34fd5c9",
+		"d82f291cde9987322c8a0c81a325e1ba6159684c",
+	}, head.Parents)
+
+	root, ok := commits.Get("5d7303c49ac984a9fec60523f2d5297682e16646")
+	require.True(t, ok)
+	assert.Equal(t, 1, root.Generation)
+	assert.Empty(t, root.Parents)
+
+	assert.Equal(t, f.Head, commits.TopoOrder()[0])
+	assert.Equal(t, f.Head, commits.DateOrder()[0])
+
+	_, ok = commits.Get("0000000000000000000000000000000000000000")
+	assert.False(t, ok)
+}
+
+func TestCommitsBasic(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range fixtures.Basic().ByObjectFormat("sha1") {
+		commits := f.Commits()
+		require.NotNil(t, commits, f.Name)
+
+		if f.Head == "" {
+			// Fixtures without a Head have histories of their own.
+			continue
+		}
+
+		head, ok := commits.Get(f.Head)
+		require.True(t, ok, f.Head)
+		assert.Equal(t, "a8d315b2b1c615d43042c3a62402b8a54288cf5c", head.Tree)
+		assert.Equal(t, int64(1428269447), head.CommitterDate.Unix())
+
+		_, offset := head.CommitterDate.Zone()
+		assert.Equal(t, 2*60*60, offset)
+	}
+}
+
+func TestCommitsReturnsNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, fixtures.ByTag("thinpack").One().Commits())
+	assert.Nil(t, fixtures.ByTag("empty").One().Commits())
+}
@@ -0,0 +1,334 @@
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+	"github.com/go-git/go-git-fixtures/v6/internal/packfile"
+)
+
+// ErrCorruptionNotApplicable is returned by Fixture.CorruptPackfile when the
+// packfile cannot be broken in the requested way.
+var ErrCorruptionNotApplicable = errors.New("corruption not applicable")
+
+// Corruption is a way of breaking a packfile, see Fixture.CorruptPackfile.
+type Corruption int
+
+const (
+	// CorruptTruncated cuts the packfile halfway through an object, which
+	// fails with FailureTruncated.
+	CorruptTruncated Corruption = iota + 1
+	// CorruptTrailer flips a bit of the trailing checksum, which fails with
+	// FailureChecksum.
+	CorruptTrailer
+	// CorruptZlib breaks the zlib header of an object's compressed data,
+	// which fails with FailureZlib.
+	CorruptZlib
+	// CorruptObjectCount declares one object less in the header than the
+	// packfile holds, so that the last object is left over after the declared
+	// ones, which fails with FailureTrailingData.
+	CorruptObjectCount
+	// CorruptOFSDeltaOffset makes an ofs-delta point to a base before the
+	// start of the packfile, which fails with FailureDeltaBase.
+	CorruptOFSDeltaOffset
+	// CorruptREFDeltaBase makes a ref-delta reference a base missing from the
+	// packfile, which fails with FailureDeltaBase.
+	CorruptREFDeltaBase
+)
+
+// PackFailure is the class of error a decoder is expected to report for a
+// corrupted packfile.
+type PackFailure int
+
+const (
+	// FailureTruncated is reported when the data ends before the packfile is
+	// complete, e.g. io.ErrUnexpectedEOF.
+	FailureTruncated PackFailure = iota + 1
+	// FailureChecksum is reported when the trailer does not match the hash
+	// of the packfile content.
+	FailureChecksum
+	// FailureZlib is reported when an object's compressed data is not a
+	// valid zlib stream, e.g. zlib.ErrHeader.
+	FailureZlib
+	// FailureDeltaBase is reported when the base of a delta cannot be found.
+	FailureDeltaBase
+	// FailureTrailingData is reported when data is left between the objects
+	// declared by the header and the trailer.
+	FailureTrailingData
+)
+
+//nolint:gochecknoglobals
+var corruptionNames = map[Corruption]string{
+	CorruptTruncated:      "truncated",
+	CorruptTrailer:        "trailer",
+	CorruptZlib:           "zlib",
+	CorruptObjectCount:    "object-count",
+	CorruptOFSDeltaOffset: "ofs-delta-offset",
+	CorruptREFDeltaBase:   "ref-delta-base",
+}
+
+func (c Corruption) String() string {
+	if name, ok := corruptionNames[c]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Corruption(%d)", int(c))
+}
+
+// Failure returns the class of error decoders should report for packfiles
+// broken with c.
+func (c Corruption) Failure() PackFailure {
+	switch c {
+	case CorruptTruncated:
+		return FailureTruncated
+	case CorruptTrailer:
+		return FailureChecksum
+	case CorruptObjectCount:
+		return FailureTrailingData
+	case CorruptZlib:
+		return FailureZlib
+	case CorruptOFSDeltaOffset, CorruptREFDeltaBase:
+		return FailureDeltaBase
+	}
+
+	return 0
+}
+
+// CorruptedPackfile is a packfile broken by Fixture.CorruptPackfile.
+type CorruptedPackfile struct {
+	Corruption Corruption
+	// Data is the content of the corrupted packfile.
+	Data []byte
+	// Offset is the offset of the object that was corrupted, or of the
+	// corrupted bytes for CorruptTrailer and CorruptObjectCount.
+	Offset int64
+}
+
+// Failure returns the class of error decoders should report for p.
+func (p *CorruptedPackfile) Failure() PackFailure {
+	return p.Corruption.Failure()
+}
+
+// CorruptOption configures Fixture.CorruptPackfile.
+type CorruptOption func(*corruptOptions)
+
+type corruptOptions struct {
+	object int
+}
+
+// WithCorruptObject selects the object to corrupt by its position in the
+// packfile, starting at 0. For CorruptOFSDeltaOffset and CorruptREFDeltaBase,
+// the first delta of the right type from that position is used. It defaults
+// to the middle object for CorruptTruncated, and to the first one otherwise.
+func WithCorruptObject(i int) CorruptOption {
+	return func(o *corruptOptions) {
+		o.object = i
+	}
+}
+
+// CorruptPackfile returns a copy of the fixture's packfile broken as
+// described by c. The result is deterministic, and only breaks the packfile
+// in the way described: the trailer of the variants which keep one is
+// recomputed to match their content.
+//
+// Returns ErrCorruptionNotApplicable if the packfile has no object that can
+// be corrupted as requested, e.g. CorruptREFDeltaBase on packs without
+// ref-deltas.
+func (f *Fixture) CorruptPackfile(c Corruption, opts ...CorruptOption) (*CorruptedPackfile, error) {
+	data, err := f.readFile(f.packfilePath("pack"))
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := f.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	hashSize, err := object.HashSize(f.ObjectFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &corruptOptions{object: 0}
+	if c == CorruptTruncated {
+		o.object = len(entries) / 2
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.object < 0 || o.object >= len(entries) {
+		return nil, fmt.Errorf("%w: object %d out of %d", ErrCorruptionNotApplicable, o.object, len(entries))
+	}
+
+	p := &CorruptedPackfile{Corruption: c, Data: slices.Clone(data), Offset: entries[o.object].Offset}
+	end := len(data) - hashSize
+
+	switch c {
+	case CorruptTruncated:
+		next := int64(end)
+		if o.object+1 < len(entries) {
+			next = entries[o.object+1].Offset
+		}
+
+		p.Data = p.Data[:(p.Offset+next)/2]
+
+		return p, nil
+	case CorruptTrailer:
+		p.Offset = int64(end)
+		p.Data[end] ^= 0x01
+
+		return p, nil
+	case CorruptZlib:
+		err = corruptZlib(p, entries[o.object], hashSize)
+	case CorruptObjectCount:
+		err = corruptObjectCount(p)
+	case CorruptOFSDeltaOffset:
+		err = corruptOFSDelta(p, entries[o.object:])
+	case CorruptREFDeltaBase:
+		err = corruptREFDelta(p, entries[o.object:], hashSize)
+	default:
+		return nil, fmt.Errorf("%w: unknown corruption %d", ErrCorruptionNotApplicable, c)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := object.NewHasher(f.ObjectFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Write(p.Data[:end])
+	copy(p.Data[end:], h.Sum(nil))
+
+	return p, nil
+}
+
+// countOffset is the offset of the object count in the packfile header,
+// after the signature and version.
+const countOffset = 8
+
+func corruptObjectCount(p *CorruptedPackfile) error {
+	header, err := packfile.ReadHeader(p.Data)
+	if err != nil {
+		return err
+	}
+
+	if header.Objects == 0 {
+		return fmt.Errorf("%w: empty packfile", ErrCorruptionNotApplicable)
+	}
+
+	count := header.Objects - 1
+	p.Offset = countOffset
+	p.Data[countOffset] = byte(count >> 24)
+	p.Data[countOffset+1] = byte(count >> 16)
+	p.Data[countOffset+2] = byte(count >> 8)
+	p.Data[countOffset+3] = byte(count)
+
+	return nil
+}
+
+// corruptZlib sets the compression method of the zlib header of e's data to
+// an invalid one.
+func corruptZlib(p *CorruptedPackfile, e packfile.Entry, hashSize int) error {
+	pos := skipVarint(p.Data, e.Offset) // type and size
+
+	switch e.Type {
+	case object.OFSDelta:
+		pos = skipVarint(p.Data, pos)
+	case object.REFDelta:
+		pos += int64(hashSize)
+	default:
+	}
+
+	p.Data[pos] &^= 0x0f
+
+	return nil
+}
+
+// corruptOFSDelta rewrites the base offset of the first ofs-delta of entries
+// that can point before the start of the packfile without changing the
+// length of its encoding.
+func corruptOFSDelta(p *CorruptedPackfile, entries []packfile.Entry) error {
+	for _, e := range entries {
+		if e.Type != object.OFSDelta {
+			continue
+		}
+
+		pos := skipVarint(p.Data, e.Offset) // type and size
+		n := int(skipVarint(p.Data, pos) - pos)
+
+		lo, hi := ofsRange(n)
+		rel := max(e.Offset+1, lo)
+
+		if rel > hi {
+			continue
+		}
+
+		encodeOFS(p.Data[pos:pos+int64(n)], rel)
+		p.Offset = e.Offset
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: no suitable ofs-delta", ErrCorruptionNotApplicable)
+}
+
+// corruptREFDelta replaces the base of the first ref-delta of entries with a
+// zero hash.
+func corruptREFDelta(p *CorruptedPackfile, entries []packfile.Entry, hashSize int) error {
+	for _, e := range entries {
+		if e.Type != object.REFDelta {
+			continue
+		}
+
+		pos := skipVarint(p.Data, e.Offset) // type and size
+		clear(p.Data[pos : pos+int64(hashSize)])
+		p.Offset = e.Offset
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: no ref-delta", ErrCorruptionNotApplicable)
+}
+
+// skipVarint returns the position following the variable-length integer at
+// pos, whose bytes have their most significant bit set but for the last.
+func skipVarint(data []byte, pos int64) int64 {
+	for data[pos]&0x80 != 0 {
+		pos++
+	}
+
+	return pos + 1
+}
+
+// ofsRange returns the range of ofs-delta offsets encoded in n bytes.
+func ofsRange(n int) (int64, int64) {
+	var lo, hi int64 = 0, 0x7f
+
+	for range n - 1 {
+		lo = hi + 1
+		hi = (hi+1)<<7 | 0x7f
+	}
+
+	return lo, hi
+}
+
+// encodeOFS encodes rel as an ofs-delta offset filling buf, which must be of
+// the length of its encoding.
+func encodeOFS(buf []byte, rel int64) {
+	i := len(buf) - 1
+	buf[i] = byte(rel & 0x7f)
+
+	for rel >>= 7; i > 0; rel >>= 7 {
+		rel--
+		i--
+		buf[i] = 0x80 | byte(rel&0x7f)
+	}
+}
@@ -0,0 +1,140 @@
+package fixtures_test
+
+import (
+	"compress/zlib"
+	"crypto/sha1" //nolint:gosec // packfile checksums are sha1.
+	"encoding/binary"
+	"io"
+	"testing"
+
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/go-git/go-git-fixtures/v6/internal/packfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorruptPackfile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		fixture    string
+		corruption fixtures.Corruption
+		failure    fixtures.PackFailure
+		want       error
+	}{
+		{"basic-ofs-delta", fixtures.CorruptTruncated, fixtures.FailureTruncated, io.ErrUnexpectedEOF},
+		// The internal decoder does not check the trailer, see
+		// TestCorruptPackfileTrailer.
+		{"basic-ofs-delta", fixtures.CorruptTrailer, fixtures.FailureChecksum, nil},
+		{"basic-ofs-delta", fixtures.CorruptZlib, fixtures.FailureZlib, zlib.ErrHeader},
+		{"basic-ofs-delta", fixtures.CorruptObjectCount, fixtures.FailureTrailingData, packfile.ErrMalformedPack},
+		{"basic-ofs-delta", fixtures.CorruptOFSDeltaOffset, fixtures.FailureDeltaBase, packfile.ErrMissingBase},
+		{"basic-ref-delta", fixtures.CorruptREFDeltaBase, fixtures.FailureDeltaBase, packfile.ErrMissingBase},
+		{"basic-ref-delta", fixtures.CorruptZlib, fixtures.FailureZlib, zlib.ErrHeader},
+		{"sha256-basic", fixtures.CorruptOFSDeltaOffset, fixtures.FailureDeltaBase, packfile.ErrMissingBase},
+	}
+
+	for _, tc := range tests {
+		f := fixtures.ByName(tc.fixture)
+		require.NotNil(t, f, tc.fixture)
+
+		t.Run(tc.fixture+"/"+tc.corruption.String(), func(t *testing.T) {
+			t.Parallel()
+
+			original := readPackfile(t, f)
+
+			p, err := f.CorruptPackfile(tc.corruption)
+			require.NoError(t, err)
+			assert.Equal(t, tc.corruption, p.Corruption)
+			assert.Equal(t, tc.failure, p.Failure())
+			assert.NotEqual(t, original, p.Data)
+			assert.Equal(t, original, readPackfile(t, f), "original packfile modified")
+
+			again, err := f.CorruptPackfile(tc.corruption)
+			require.NoError(t, err)
+			assert.Equal(t, p.Data, again.Data, "not deterministic")
+
+			_, err = packfile.Decode(f.ObjectFormat, p.Data)
+			if tc.want == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestCorruptPackfileTrailer(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByName("basic-ofs-delta")
+	p, err := f.CorruptPackfile(fixtures.CorruptTrailer)
+	require.NoError(t, err)
+
+	end := len(p.Data) - sha1.Size
+	assert.Equal(t, int64(end), p.Offset)
+
+	sum := sha1.Sum(p.Data[:end]) //nolint:gosec // packfile checksums are sha1.
+	assert.NotEqual(t, sum[:], p.Data[end:])
+}
+
+func TestCorruptPackfileObjectCount(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByName("basic-ofs-delta")
+	p, err := f.CorruptPackfile(fixtures.CorruptObjectCount)
+	require.NoError(t, err)
+
+	assert.Equal(t, f.ObjectsCount-1, int32(binary.BigEndian.Uint32(p.Data[8:12]))) //nolint:gosec // small count.
+
+	// The trailer still matches the content, so the failure is only found
+	// once the declared objects are read and the last one is left over.
+	end := len(p.Data) - sha1.Size
+	sum := sha1.Sum(p.Data[:end]) //nolint:gosec // packfile checksums are sha1.
+	assert.Equal(t, sum[:], p.Data[end:])
+
+	_, err = packfile.Decode(f.ObjectFormat, p.Data)
+	require.ErrorContains(t, err, "trailing bytes after last object")
+}
+
+func TestCorruptPackfileTruncatedObject(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByName("basic-ofs-delta")
+	entries := f.ScannerEntries()
+	require.Greater(t, len(entries), 4)
+
+	p, err := f.CorruptPackfile(fixtures.CorruptTruncated, fixtures.WithCorruptObject(3))
+	require.NoError(t, err)
+	assert.Equal(t, entries[3].Offset, p.Offset)
+	assert.Greater(t, int64(len(p.Data)), p.Offset)
+	assert.Less(t, int64(len(p.Data)), entries[4].Offset)
+}
+
+func TestCorruptPackfileNotApplicable(t *testing.T) {
+	t.Parallel()
+
+	_, err := fixtures.ByName("basic-ofs-delta").CorruptPackfile(fixtures.CorruptREFDeltaBase)
+	require.ErrorIs(t, err, fixtures.ErrCorruptionNotApplicable)
+
+	_, err = fixtures.ByName("basic-ref-delta").CorruptPackfile(fixtures.CorruptOFSDeltaOffset)
+	require.ErrorIs(t, err, fixtures.ErrCorruptionNotApplicable)
+
+	_, err = fixtures.ByName("basic-ofs-delta").CorruptPackfile(
+		fixtures.CorruptZlib, fixtures.WithCorruptObject(1<<20))
+	require.ErrorIs(t, err, fixtures.ErrCorruptionNotApplicable)
+}
+
+func readPackfile(t *testing.T, f *fixtures.Fixture) []byte {
+	t.Helper()
+
+	file, err := f.Packfile()
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+
+	return data
+}
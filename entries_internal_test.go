@@ -0,0 +1,47 @@
+package fixtures
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEntriesMatchTables cross-checks the computed entries against the
+// hand-maintained tables.
+func TestEntriesMatchTables(t *testing.T) {
+	t.Parallel()
+
+	for hash, d := range packfileEntries {
+		t.Run(hash, func(t *testing.T) {
+			t.Parallel()
+
+			f := ByPackfileHash(hash).One()
+			require.NotNil(t, f)
+
+			want := make(PackfileEntry, len(d.hashes))
+			for i, h := range d.hashes {
+				want[h] = d.offsets[i]
+			}
+
+			assert.Equal(t, want, f.Entries())
+		})
+	}
+}
+
+// TestScannerEntriesMatchTables cross-checks the computed scanner entries
+// against the hand-maintained tables.
+func TestScannerEntriesMatchTables(t *testing.T) {
+	t.Parallel()
+
+	for hash, want := range scannerEntries {
+		t.Run(hash, func(t *testing.T) {
+			t.Parallel()
+
+			f := ByPackfileHash(hash).One()
+			require.NotNil(t, f)
+
+			assert.Equal(t, want, f.ScannerEntries())
+		})
+	}
+}
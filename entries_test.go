@@ -27,10 +27,10 @@ func TestEntries(t *testing.T) {
 	}
 }
 
-func TestEntriesReturnsNilForUnregisteredPackfile(t *testing.T) {
+func TestEntriesReturnsNilWithoutPackfile(t *testing.T) {
 	t.Parallel()
 
-	f := fixtures.ByTag("notes").One()
+	f := fixtures.ByTag("worktree").One()
 	require.NotNil(t, f)
 	assert.Nil(t, f.Entries())
 }
@@ -84,10 +84,10 @@ func TestScannerEntries(t *testing.T) {
 	}
 }
 
-func TestScannerEntriesReturnsNilForUnregisteredPackfile(t *testing.T) {
+func TestScannerEntriesReturnsNilWithoutPackfile(t *testing.T) {
 	t.Parallel()
 
-	f := fixtures.ByTag("notes").One()
+	f := fixtures.ByTag("worktree").One()
 	require.NotNil(t, f)
 	assert.Nil(t, f.ScannerEntries())
 }
@@ -140,3 +140,52 @@ func TestEntriesConsistentWithScannerEntries(t *testing.T) {
 		})
 	}
 }
+
+func TestEntriesAllPackfiles(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range fixtures.All() {
+		if f.PackfileHash == "" {
+			continue
+		}
+
+		t.Run(f.PackfileHash, func(t *testing.T) {
+			t.Parallel()
+
+			scannerEntries := f.ScannerEntries()
+			require.NotEmpty(t, scannerEntries)
+
+			entries := f.Entries()
+			if f.Is("thinpack") {
+				assert.Nil(t, entries, "thin packs have no idx")
+
+				return
+			}
+
+			require.Len(t, entries, len(scannerEntries))
+
+			offsets := make(map[int64]bool, len(entries))
+			for _, offset := range entries {
+				offsets[offset] = true
+			}
+
+			for _, se := range scannerEntries {
+				assert.True(t, offsets[se.Offset], "no idx entry at offset %d", se.Offset)
+
+				if se.Hash != "" {
+					assert.Equal(t, se.Offset, entries[se.Hash])
+				}
+			}
+		})
+	}
+}
+
+func TestEntriesTags(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range fixtures.All() {
+		hasPack := f.PackfileHash != ""
+		assert.Equal(t, hasPack, f.Is(fixtures.TagScannerEntries), f.Name)
+		assert.Equal(t, hasPack && !f.Is(fixtures.TagThinpack), f.Is(fixtures.TagPackfileEntries), f.Name)
+	}
+}
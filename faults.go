@@ -0,0 +1,221 @@
+package fixtures
+
+import (
+	"errors"
+	"math/rand/v2"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-git-fixtures/v6/internal/faultfs"
+)
+
+// ErrInjectedFault is the error returned by operations failed by a
+// FaultRule without an Err.
+var ErrInjectedFault = errors.New("injected fault")
+
+// FaultOp is a kind of filesystem operation a FaultRule applies to. Values
+// can be combined, e.g. FaultRead|FaultWrite.
+type FaultOp uint32
+
+const (
+	// FaultOpen is for Create, Open, OpenFile and TempFile.
+	FaultOpen = FaultOp(faultfs.Open)
+	// FaultRead is for Read and ReadAt on files.
+	FaultRead = FaultOp(faultfs.Read)
+	// FaultWrite is for Write, WriteAt and Truncate on files.
+	FaultWrite = FaultOp(faultfs.Write)
+	// FaultClose is for Close on files. Files are closed even when it fails.
+	FaultClose = FaultOp(faultfs.Close)
+	// FaultSync is for Sync on files.
+	FaultSync = FaultOp(faultfs.Sync)
+	// FaultStat is for Stat and Lstat, on filesystems and files.
+	FaultStat = FaultOp(faultfs.Stat)
+	// FaultRename is for Rename, matched against both the old and new path.
+	// A Rename counts as a single operation.
+	FaultRename = FaultOp(faultfs.Rename)
+	// FaultRemove is for Remove.
+	FaultRemove = FaultOp(faultfs.Remove)
+	// FaultMkdir is for MkdirAll.
+	FaultMkdir = FaultOp(faultfs.Mkdir)
+	// FaultReadDir is for ReadDir.
+	FaultReadDir = FaultOp(faultfs.ReadDir)
+	// FaultSymlink is for Symlink and Readlink.
+	FaultSymlink = FaultOp(faultfs.Symlink)
+	// FaultChmod is for Chmod, Chown, Lchown and Chtimes.
+	FaultChmod = FaultOp(faultfs.Chmod)
+
+	// FaultAll is for every operation.
+	FaultAll = FaultOpen | FaultRead | FaultWrite | FaultClose | FaultSync | FaultStat |
+		FaultRename | FaultRemove | FaultMkdir | FaultReadDir | FaultSymlink | FaultChmod
+)
+
+// FaultRule describes the operations to fail on a filesystem returned with
+// WithFaults. A rule matches an operation if both its Ops and Path match;
+// of the matching operations, it fails those selected by After, Probability
+// and Times.
+type FaultRule struct {
+	// Ops are the operations the rule applies to. Zero applies to all of
+	// them.
+	Ops FaultOp
+	// Path is a path.Match pattern of the paths the rule applies to,
+	// relative to the root of the filesystem and separated by slashes, e.g.
+	// "objects/pack/*.pack". Patterns without a slash are also matched
+	// against the base name, so "*.pack" matches every packfile. Empty
+	// matches any path.
+	Path string
+	// Err is the error the operation fails with, wrapped in a *fs.PathError
+	// or *os.LinkError, e.g. syscall.ENOSPC or syscall.EIO. It defaults to
+	// ErrInjectedFault, unless Short is set.
+	Err error
+	// Short makes reads return half of the bytes requested and writes write
+	// half of them and fail with io.ErrShortWrite, instead of failing with
+	// Err. Other operations are let through.
+	Short bool
+	// After is the number of matching operations let through before the rule
+	// starts failing them.
+	After int
+	// Probability is the chance of failing each matching operation, from
+	// the source seeded by WithFaultSeed. Zero fails all of them.
+	Probability float64
+	// Times is the maximum number of operations failed by the rule. Zero is
+	// unlimited.
+	Times int
+}
+
+// WithFaults returns the option of wrapping the returned filesystem, or
+// file for Packfile, Idx and Rev, in one failing operations as described by
+// rules, to test how callers cope with I/O errors:
+//
+//	fs, err := f.DotGit(fixtures.WithFaults(fixtures.FaultRule{
+//		Ops:  fixtures.FaultRead,
+//		Path: "*.pack",
+//		Err:  syscall.EIO,
+//	}))
+//
+// Rules are checked in order, and the first to fail an operation applies.
+// Faults only depend on the rules, the seed and the sequence of operations,
+// so they are deterministic as long as operations are not concurrent. Files,
+// and filesystems returned by Chroot, share the rules of the filesystem
+// they come from, with paths relative to their own root.
+//
+// Faults are injected after the fixture is extracted, so they only affect
+// the caller's operations.
+func WithFaults(rules ...FaultRule) Option {
+	return func(o *options) {
+		o.faults = append(o.faults, rules...)
+	}
+}
+
+// WithFaultSeed returns the option of seeding the source used for the
+// Probability of the rules set by WithFaults. It defaults to 0.
+func WithFaultSeed(seed uint64) Option {
+	return func(o *options) {
+		o.faultSeed = seed
+	}
+}
+
+// withFaults wraps fs as set by WithFaults.
+func (o *options) withFaults(fs billy.Filesystem) billy.Filesystem {
+	if len(o.faults) == 0 {
+		return fs
+	}
+
+	return faultfs.New(fs, newFaults(o.faults, o.faultSeed).inject)
+}
+
+// withFileFaults wraps file, opened from name, as set by WithFaults.
+func (o *options) withFileFaults(file billy.File, name string) billy.File {
+	if len(o.faults) == 0 {
+		return file
+	}
+
+	return faultfs.NewFile(file, name, newFaults(o.faults, o.faultSeed).inject)
+}
+
+// faults keeps the state of the rules of a filesystem.
+type faults struct {
+	mu    sync.Mutex
+	rules []FaultRule
+	// matched and failed are the number of operations matched and failed by
+	// each rule.
+	matched []int
+	failed  []int
+	rand    *rand.Rand
+}
+
+func newFaults(rules []FaultRule, seed uint64) *faults {
+	return &faults{
+		mu:      sync.Mutex{},
+		rules:   rules,
+		matched: make([]int, len(rules)),
+		failed:  make([]int, len(rules)),
+		rand:    rand.New(rand.NewPCG(seed, seed)), //nolint:gosec // faults need not be unpredictable.
+	}
+}
+
+func (f *faults) inject(op faultfs.Op, names ...string) faultfs.Fault {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, r := range f.rules {
+		if !r.matches(FaultOp(op), names) {
+			continue
+		}
+
+		f.matched[i]++
+
+		switch {
+		case f.matched[i] <= r.After:
+			continue
+		case r.Times > 0 && f.failed[i] >= r.Times:
+			continue
+		case r.Probability > 0 && f.rand.Float64() >= r.Probability:
+			continue
+		}
+
+		f.failed[i]++
+
+		if r.Short {
+			return faultfs.Fault{Err: nil, Short: true}
+		}
+
+		err := r.Err
+		if err == nil {
+			err = ErrInjectedFault
+		}
+
+		return faultfs.Fault{Err: err, Short: false}
+	}
+
+	return faultfs.Fault{Err: nil, Short: false}
+}
+
+// matches reports whether r matches the operation op on any of names.
+func (r FaultRule) matches(op FaultOp, names []string) bool {
+	if r.Ops != 0 && r.Ops&op == 0 {
+		return false
+	}
+
+	return slices.ContainsFunc(names, r.matchesPath)
+}
+
+func (r FaultRule) matchesPath(name string) bool {
+	if r.Path == "" {
+		return true
+	}
+
+	if ok, _ := path.Match(r.Path, name); ok {
+		return true
+	}
+
+	if strings.Contains(r.Path, "/") {
+		return false
+	}
+
+	ok, _ := path.Match(r.Path, path.Base(name))
+
+	return ok
+}
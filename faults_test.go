@@ -0,0 +1,188 @@
+package fixtures_test
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/util"
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFaults(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByName("basic-ofs-delta")
+
+	fs, err := f.DotGit(fixtures.WithFaults(fixtures.FaultRule{
+		Ops:  fixtures.FaultRead,
+		Path: "*.pack",
+		Err:  syscall.EIO,
+	}))
+	require.NoError(t, err)
+
+	pack := "objects/pack/pack-" + f.PackfileHash + ".pack"
+
+	_, err = util.ReadFile(fs, pack)
+	require.ErrorIs(t, err, syscall.EIO)
+
+	_, err = util.ReadFile(fs, "objects/pack/pack-"+f.PackfileHash+".idx")
+	require.NoError(t, err)
+
+	_, err = fs.Stat(pack)
+	require.NoError(t, err)
+
+	// Filesystems are not affected by the faults of others.
+	fs, err = f.DotGit()
+	require.NoError(t, err)
+
+	_, err = util.ReadFile(fs, pack)
+	require.NoError(t, err)
+}
+
+func TestWithFaultsDefaultErr(t *testing.T) {
+	t.Parallel()
+
+	fs, err := fixtures.ByName("basic-ofs-delta").DotGit(fixtures.WithFaults(fixtures.FaultRule{
+		Ops:  fixtures.FaultWrite | fixtures.FaultRename,
+		Path: "refs/heads/*",
+	}))
+	require.NoError(t, err)
+
+	err = util.WriteFile(fs, "refs/heads/new", []byte("x"), 0o644)
+	require.ErrorIs(t, err, fixtures.ErrInjectedFault)
+
+	err = util.WriteFile(fs, "refs/tags/new", []byte("x"), 0o644)
+	require.NoError(t, err)
+
+	err = fs.Rename("refs/tags/new", "refs/heads/new")
+	require.ErrorIs(t, err, fixtures.ErrInjectedFault)
+
+	var lerr *os.LinkError
+	require.ErrorAs(t, err, &lerr)
+}
+
+func TestWithFaultsAfterTimes(t *testing.T) {
+	t.Parallel()
+
+	fs, err := fixtures.ByName("basic-ofs-delta").DotGit(fixtures.WithFaults(fixtures.FaultRule{
+		Ops:   fixtures.FaultStat,
+		Err:   syscall.EIO,
+		After: 2,
+		Times: 1,
+	}))
+	require.NoError(t, err)
+
+	var errs []error
+
+	for range 4 {
+		_, err := fs.Stat("HEAD")
+		errs = append(errs, err)
+	}
+
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	require.ErrorIs(t, errs[2], syscall.EIO)
+	assert.NoError(t, errs[3])
+}
+
+func TestWithFaultsAfterRename(t *testing.T) {
+	t.Parallel()
+
+	// The rule matches both paths, yet each Rename counts once.
+	fs, err := fixtures.ByName("basic-ofs-delta").DotGit(fixtures.WithFaults(fixtures.FaultRule{
+		Ops:   fixtures.FaultRename,
+		After: 1,
+	}))
+	require.NoError(t, err)
+
+	require.NoError(t, util.WriteFile(fs, "a", []byte("x"), 0o644))
+	require.NoError(t, fs.Rename("a", "b"))
+	require.ErrorIs(t, fs.Rename("b", "c"), fixtures.ErrInjectedFault)
+}
+
+func TestWithFaultsSeed(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByName("basic-ofs-delta")
+
+	outcomes := func(seed uint64) []bool {
+		fs, err := f.DotGit(
+			fixtures.WithFaults(fixtures.FaultRule{Ops: fixtures.FaultStat, Probability: 0.5}),
+			fixtures.WithFaultSeed(seed),
+		)
+		require.NoError(t, err)
+
+		out := make([]bool, 0, 64)
+		for range 64 {
+			_, err := fs.Stat("HEAD")
+			out = append(out, err == nil)
+		}
+
+		return out
+	}
+
+	first := outcomes(1)
+	assert.Equal(t, first, outcomes(1))
+	assert.NotEqual(t, first, outcomes(2))
+	assert.Contains(t, first, true)
+	assert.Contains(t, first, false)
+}
+
+func TestWithFaultsShortRead(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByName("basic-ofs-delta")
+
+	for name, open := range map[string]func(...fixtures.Option) (billy.File, error){
+		"pack": f.Packfile,
+		"idx":  f.Idx,
+		"rev":  f.Rev,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := open(fixtures.WithFaults(fixtures.FaultRule{Ops: fixtures.FaultRead, Short: true}))
+			require.NoError(t, err)
+
+			defer file.Close()
+
+			buf := make([]byte, 8)
+			n, err := file.Read(buf)
+			require.NoError(t, err)
+			assert.Equal(t, 4, n)
+
+			// Readers that retry on short reads still get the whole file.
+			rest, err := io.ReadAll(file)
+			require.NoError(t, err)
+
+			full, err := open()
+			require.NoError(t, err)
+
+			defer full.Close()
+
+			want, err := io.ReadAll(full)
+			require.NoError(t, err)
+			assert.Equal(t, want, append(buf[:n], rest...))
+		})
+	}
+}
+
+func TestWithFaultsWorktreeDotGit(t *testing.T) {
+	t.Parallel()
+
+	// The .git directory of worktree fixtures is a Chroot of the worktree,
+	// and paths are matched relative to it.
+	fs, err := fixtures.ByName("basic-worktree").DotGit(fixtures.WithFaults(fixtures.FaultRule{
+		Ops:  fixtures.FaultOpen,
+		Path: "HEAD",
+	}))
+	require.NoError(t, err)
+
+	_, err = fs.Open("HEAD")
+	require.ErrorIs(t, err, fixtures.ErrInjectedFault)
+}
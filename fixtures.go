@@ -11,23 +11,9 @@ import (
 
 	"github.com/go-git/go-billy/v6"
 	"github.com/go-git/go-billy/v6/embedfs"
-	"github.com/go-git/go-git-fixtures/v6/internal/tgz"
 )
 
 const (
-	tagDotGit          = ".git"
-	tagPackfile        = "packfile"
-	tagRevV1           = "rev-v1"
-	tagIDXV2           = "idx-v2"
-	tagPackV2          = "pack-v2"
-	tagIndexV2         = "index-v2"
-	tagIndexExtTree    = "index-ext-tree"
-	tagWorktree        = "worktree"
-	tagDiffTree        = "diff-tree"
-	tagPackfileEntries = "packfile-entries"
-	tagScannerEntries  = "scanner-entries"
-	tagOFSDelta        = "ofs-delta"
-
 	basicGitURL          = "https://github.com/git-fixtures/basic.git"
 	basicGitHead         = "6ecf0ef2c2dffb796033e5a02219af86ec6584e5"
 	basicOFSPackfileHash = "a3fed42da1e8189a077c0e6846c040dcf73fc9dd"
@@ -45,9 +31,10 @@ var data embed.FS
 
 //nolint:gochecknoglobals
 var fixtures = Fixtures{{
-	Tags: []string{
-		tagPackfile, tagPackV2, tagIDXV2, tagIndexV2, tagOFSDelta,
-		tagDotGit, "root-reference", tagIndexExtTree,
+	Name: "root-references",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagIndexV2,
+		TagOFSDelta, TagDotGit, TagRootReference, TagIndexExtTree,
 	},
 	URL:          "https://github.com/git-fixtures/root-references.git",
 	Head:         basicGitHead,
@@ -56,9 +43,10 @@ var fixtures = Fixtures{{
 	ObjectsCount: 68,
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags: []string{
-		tagPackfile, tagPackfileEntries, tagScannerEntries, tagPackV2, tagIDXV2, tagIndexV2, tagOFSDelta,
-		tagDotGit, tagIndexExtTree,
+	Name: "basic-ofs-delta",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagIndexV2, TagOFSDelta,
+		TagDotGit, TagIndexExtTree,
 	},
 	URL:          basicGitURL,
 	Head:         basicGitHead,
@@ -67,8 +55,9 @@ var fixtures = Fixtures{{
 	ObjectsCount: 31,
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags: []string{
-		tagDotGit, tagIndexExtTree, "reftable", tagIndexV2,
+	Name: "basic-reftable",
+	Tags: []Tag{
+		TagDotGit, TagIndexExtTree, TagReftable, TagIndexV2,
 	},
 	URL:          basicGitURL,
 	Head:         basicGitHead,
@@ -76,9 +65,10 @@ var fixtures = Fixtures{{
 	ObjectsCount: 31,
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags: []string{
-		tagPackfile, tagPackfileEntries, tagScannerEntries, tagPackV2, tagIDXV2, tagIndexV2, "ref-delta",
-		tagDotGit, tagRevV1,
+	Name: "basic-ref-delta",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagIndexV2, TagREFDelta,
+		TagDotGit, TagRevV1,
 	},
 	URL:          basicGitURL,
 	Head:         basicGitHead,
@@ -87,9 +77,10 @@ var fixtures = Fixtures{{
 	ObjectsCount: 31,
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags: []string{
-		tagPackfile, tagPackV2, tagIDXV2, tagIndexV2, tagOFSDelta,
-		tagDotGit, "single-branch", tagRevV1, tagIndexExtTree,
+	Name: "basic-single-branch",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagIndexV2,
+		TagOFSDelta, TagDotGit, TagSingleBranch, TagRevV1, TagIndexExtTree,
 	},
 	URL:          basicGitURL,
 	Head:         basicGitHead,
@@ -98,47 +89,55 @@ var fixtures = Fixtures{{
 	ObjectsCount: 28,
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagDotGit, "merge-conflict", tagIndexV2, "index-ext-none"},
+	Name:         "basic-merge-conflict",
+	Tags:         []Tag{TagDotGit, TagMergeConflict, TagIndexV2, TagIndexExtNone},
 	URL:          basicGitURL,
 	DotGitHash:   "4870d54b5b04e43da8cf99ceec179d9675494af8",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagDotGit, "resolve-undo", tagIndexV2, "index-ext-reuc"},
+	Name:         "basic-resolve-undo",
+	Tags:         []Tag{TagDotGit, TagResolveUndo, TagIndexV2, TagIndexExtREUC},
 	URL:          basicGitURL,
 	DotGitHash:   "df6781fd40b8f4911d70ce71f8387b991615cd6d",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagDotGit, "intent-to-add", "index-v3", tagIndexExtTree},
+	Name:         "basic-index-v3",
+	Tags:         []Tag{TagDotGit, TagIntentToAdd, TagIndexV3, TagIndexExtTree},
 	URL:          basicGitURL,
 	DotGitHash:   "4e7600af05c3356e8b142263e127b76f010facfc",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagDotGit, "index-v4", tagIndexExtTree},
+	Name:         "basic-index-v4",
+	Tags:         []Tag{TagDotGit, TagIndexV4, TagIntentToAdd, TagIndexExtTree},
 	URL:          basicGitURL,
 	DotGitHash:   "935e5ac17c41c309c356639816ea0694a568c484",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags: []string{
-		tagDotGit, "end-of-index-entry", tagIndexV2, "index-ext-eoie",
-		tagIndexExtTree,
+	Name: "basic-eoie",
+	Tags: []Tag{
+		TagDotGit, TagEndOfIndexEntry, TagIndexV2, TagIndexExtEOIE,
+		TagIndexExtTree,
 	},
 	URL:          basicGitURL,
 	DotGitHash:   "ab06771a67110b976953d34400d4dbc465ccd2d9",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagWorktree},
+	Name:         "basic-worktree",
+	Tags:         []Tag{TagWorktree, TagIndexV2, TagIndexExtTree},
 	URL:          basicGitURL,
 	WorktreeHash: "d2e42ddd68eacbb6034e7724e0dd4117ff1f01ee",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagWorktree, "submodule"},
+	Name:         "submodule",
+	Tags:         []Tag{TagWorktree, TagSubmodule, TagIndexV2, TagIndexExtTree},
 	URL:          "https://github.com/git-fixtures/submodule.git",
 	WorktreeHash: "8b4d55c85677b6b94bef2e46832ed2174ed6ecaf",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags: []string{
-		tagPackfile, tagPackV2, tagIDXV2, tagIndexV2, tagDotGit, "unpacked",
-		"multi-packfile", tagIndexExtTree,
+	Name: "go-git",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagIndexV2,
+		TagDotGit, TagUnpacked, TagMultiPackfile, TagIndexExtTree,
 	},
 	URL:          "https://github.com/src-d/go-git.git",
 	Head:         "e8788ad9165781196e917292d6055cba1d78664e",
@@ -147,9 +146,10 @@ var fixtures = Fixtures{{
 	ObjectsCount: 2133,
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags: []string{
-		tagPackfile, tagPackV2, tagIDXV2, tagIndexV2, tagDotGit, "tags",
-		tagIndexExtTree,
+	Name: "tags",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagIndexV2,
+		TagDotGit, TagTags, TagIndexExtTree,
 	},
 	URL:          "https://github.com/git-fixtures/tags.git",
 	Head:         "f7b877701fbf855b44c0a9e86f3fdce2c298b07f",
@@ -158,21 +158,24 @@ var fixtures = Fixtures{{
 	ObjectsCount: 7,
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagPackfile, tagPackV2, tagIDXV2, tagRevV1},
+	Name:         "spinnaker",
+	Tags:         []Tag{TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagRevV1},
 	URL:          "https://github.com/spinnaker/spinnaker.git",
 	Head:         "06ce06d0fc49646c4de733c45b7788aabad98a6f",
 	PackfileHash: "f2e0a8889a746f7600e07d2246a2e29a72f696be",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagPackfile, tagPackV2, tagIDXV2, tagRevV1},
+	Name:         "desk",
+	Tags:         []Tag{TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagRevV1},
 	URL:          "https://github.com/jamesob/desk.git",
 	Head:         "d2313db6e7ca7bac79b819d767b2a1449abb0a5d",
 	PackfileHash: "4ec6344877f494690fc800aceaf2ca0e86786acb",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags: []string{
-		tagPackfile, tagPackV2, tagIDXV2, tagIndexV2, "empty-folder",
-		tagRevV1, tagIndexExtTree,
+	Name: "empty-folder",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagIndexV2,
+		TagEmptyFolder, TagRevV1, TagIndexExtTree,
 	},
 	URL:          "https://github.com/cpcs499/Final_Pres_P.git",
 	Head:         "70bade703ce556c2c7391a8065c45c943e8b6bc3",
@@ -180,121 +183,173 @@ var fixtures = Fixtures{{
 	DotGitHash:   "e1580a78f7d36791249df76df8a2a2613d629902",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagPackfile, tagPackV2, tagIDXV2, tagDiffTree, tagRevV1},
+	Name: "gem-builder",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagDiffTree,
+		TagRevV1,
+	},
 	URL:          "https://github.com/github/gem-builder.git",
 	PackfileHash: "1ea0b3971fd64fdcdf3282bfb58e8cf10095e4e6",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagPackfile, tagPackV2, tagIDXV2, tagDiffTree},
+	Name:         "example-branches",
+	Tags:         []Tag{TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagDiffTree},
 	URL:          "https://github.com/githubtraining/example-branches.git",
 	PackfileHash: "bb8ee94710d3fa39379a630f76812c187217b312",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagPackfile, tagPackV2, tagIDXV2, tagDiffTree},
+	Name:         "rumprun-xen",
+	Tags:         []Tag{TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagDiffTree},
 	URL:          "https://github.com/rumpkernel/rumprun-xen.git",
 	PackfileHash: "7861f2632868833a35fe5e4ab94f99638ec5129b",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagPackfile, tagPackV2, tagIDXV2, tagDiffTree, tagRevV1},
+	Name: "skeetr",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagDiffTree,
+		TagRevV1,
+	},
 	URL:          "https://github.com/mcuadros/skeetr.git",
 	PackfileHash: "36ef7a2296bfd526020340d27c5e1faa805d8d38",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagPackfile, tagPackV2, tagIDXV2, tagDiffTree, tagRevV1},
+	Name: "litemock",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagDiffTree,
+		TagRevV1,
+	},
 	URL:          "https://github.com/dezfowler/LiteMock.git",
 	PackfileHash: "0d9b6cfc261785837939aaede5986d7a7c212518",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagPackfile, tagPackV2, tagIDXV2, tagDiffTree, tagRevV1},
+	Name: "storable",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagDiffTree,
+		TagRevV1,
+	},
 	URL:          "https://github.com/tyba/storable.git",
 	PackfileHash: "0d3d824fb5c930e7e7e1f0f399f2976847d31fd3",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagPackfile, tagPackV2, tagIDXV2, tagDiffTree, tagRevV1},
+	Name: "ts3",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagDiffTree,
+		TagRevV1,
+	},
 	URL:          "https://github.com/toqueteos/ts3.git",
 	PackfileHash: "21b33a26eb7ffbd35261149fe5d886b9debab7cb",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{"empty", tagDotGit, tagIndexV2},
+	Name:         "empty",
+	Tags:         []Tag{TagEmpty, TagDotGit, TagIndexV2},
 	URL:          "https://github.com/git-fixtures/empty.git",
 	DotGitHash:   "bf3fedcc8e20fd0dec9172987ceea0038d17b516",
 	ObjectsCount: 0,
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagWorktree, "alternates"},
+	Name:         "alternates",
+	Tags:         []Tag{TagWorktree, TagAlternates},
 	WorktreeHash: "a6b6ff89c593f042347113203ead1c14ab5733ce",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagWorktree, "dirty"},
+	Name:         "dirty",
+	Tags:         []Tag{TagWorktree, TagDirty},
 	WorktreeHash: "7203669c66103305e56b9dcdf940a7fbeb515f28",
 	ObjectFormat: objectFormatSHA1,
 }, {
 	// standalone packfile that does not have any dependencies nor is part of any other fixture repo.
-	Tags:         []string{tagPackfile, tagPackV2, tagIDXV2, "standalone"},
+	Name: "standalone",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagStandalone,
+	},
 	PackfileHash: "3638209d310e10ea8d90c362d568be65dd5e03a6",
 	ObjectFormat: objectFormatSHA1,
 }, {
 	// adds commit on top of spinnaker fixture 06ce06d0fc49646c4de733c45b7788aabad98a6f via a thin pack.
-	Tags:         []string{"thinpack"},
+	Name:         "thinpack",
+	Tags:         []Tag{TagThinpack, TagScannerEntries},
 	PackfileHash: "ee4fef0ef8be5053ebae4ce75acf062ddf3031fb",
 	Head:         "ee372bb08322c1e6e7c6c4f953cc6bf72784e7fb", // the thin pack adds this commit.
 	ObjectFormat: objectFormatSHA1,
+	dependencies: []string{"spinnaker"},
 }, {
-	Tags:         []string{"merge-base", tagIndexV2, "index-ext-reuc", tagIndexExtTree},
+	Name:         "merge-base",
+	Tags:         []Tag{TagMergeBase, TagIndexV2, TagIndexExtREUC, TagIndexExtTree},
 	DotGitHash:   "26baa505b9f6fb2024b9999c140b75514718c988",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{"commit-graph", tagIndexV2, tagIndexExtTree},
+	Name:         "commit-graph",
+	Tags:         []Tag{TagCommitGraph, TagIndexV2, TagIndexExtTree, TagPackfileEntries, TagScannerEntries},
 	Head:         "b9d69064b190e7aedccf84731ca1d917871f8a1c",
 	PackfileHash: "769137af7784db501bca677fbd56fef8b52515b7",
 	DotGitHash:   "cf717ccadce761d60bb4a8557a7b9a2efd23816a",
 	ObjectsCount: 31,
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{"commit-graph-chain", tagIndexV2, tagIndexExtTree},
+	Name: "commit-graph-chain",
+	Tags: []Tag{
+		TagCommitGraphChain, TagIndexV2, TagIndexExtTree, TagPackfileEntries, TagScannerEntries,
+	},
 	Head:         "b9d69064b190e7aedccf84731ca1d917871f8a1c",
 	PackfileHash: "769137af7784db501bca677fbd56fef8b52515b7",
 	DotGitHash:   "00a1fc100787506f842e55511994f08df2c2cd66",
 	ObjectsCount: 31,
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{"commit-graph-chain-2", tagRevV1, tagIndexV2, tagIndexExtTree},
+	Name: "commit-graph-chain-2",
+	Tags: []Tag{
+		TagCommitGraphChain2, TagRevV1, TagIndexV2, TagIndexExtTree, TagPackfileEntries,
+		TagScannerEntries,
+	},
 	Head:         "ec6f456c0e8c7058a29611429965aa05c190b54b",
 	PackfileHash: "06ede69e9eba9f1af36eeee184402dc3ad705cd7",
 	DotGitHash:   "77b6511a6e67c99162ebcecd2763a9a19a7ad429",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagWorktree, "linked-worktree"},
+	Name:         "linked-worktree",
+	Tags:         []Tag{TagWorktree, TagLinkedWorktree},
 	WorktreeHash: "363d996b02d9c3b598f0176619f5c6a44a82480a",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagWorktree, "main-branch", "no-master-head"},
+	Name:         "main-branch",
+	Tags:         []Tag{TagWorktree, TagMainBranch, TagNoMasterHead, TagIndexV2, TagIndexExtTree},
 	WorktreeHash: "e3b91f99d8d050cac81d84fbef89172f58eeb745",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagPackfile, tagPackV2, tagIDXV2, "codecommit"},
+	Name: "codecommit",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagCodeCommit,
+	},
 	PackfileHash: "9733763ae7ee6efcf452d373d6fff77424fb1dcc",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagPackfile, tagPackV2, tagIDXV2, "delta-before-base"},
+	Name: "delta-before-base",
+	Tags: []Tag{
+		TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2,
+		TagDeltaBeforeBase,
+	},
 	PackfileHash: "90fedc00729b64ea0d0406db861be081cda25bbf",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagPackfile, tagScannerEntries, tagRevV1},
+	Name:         "sha256-scanner-entries",
+	Tags:         []Tag{TagPackfile, TagPackfileEntries, TagScannerEntries, TagRevV1},
 	PackfileHash: "407497645643e18a7ba56c6132603f167fe9c51c00361ee0c81d74a8f55d0ee2",
 	ObjectsCount: 5,
 	ObjectFormat: objectFormatSHA256,
 }, {
-	Tags:         []string{tagPackfile, tagPackV2, tagIDXV2, "notes"},
+	Name:         "notes",
+	Tags:         []Tag{TagPackfile, TagPackfileEntries, TagScannerEntries, TagPackV2, TagIDXV2, TagNotes},
 	PackfileHash: "bc4b855a55cae7703c023d4e36e3a7c9f5d84491",
 	ObjectFormat: objectFormatSHA1,
 }, {
-	Tags:         []string{tagDotGit, tagIndexV2, tagIndexExtTree},
+	Name:         "sha256",
+	Tags:         []Tag{TagDotGit, TagIndexV2, TagIndexExtTree},
 	URL:          "https://gitlab.com/pjbgf/sha256.git",
 	DotGitHash:   "40143428b59fe03546fabba0603268bba3b3c58b",
 	ObjectFormat: objectFormatSHA256,
 }, {
-	Tags:         []string{tagPackfile, tagPackfileEntries, tagDotGit},
+	Name:         "sha256-basic",
+	Tags:         []Tag{TagPackfile, TagPackfileEntries, TagScannerEntries, TagDotGit},
 	URL:          basicGitURL,
 	Head:         "4fef4adac3be863b9b94613016bdd8e53f67f6d7577234e028bc9d24c5a6a27c",
 	PackfileHash: "c88dfe1663bd216e278d5bb3c8decd0a4bb174a6204585dc44b7c7a05fceed55",
@@ -302,10 +357,53 @@ var fixtures = Fixtures{{
 	ObjectsCount: 31,
 	ObjectFormat: objectFormatSHA256,
 }, {
-	Tags:         []string{tagWorktree, "submodule"},
+	Name:         "sha256-submodule",
+	Tags:         []Tag{TagWorktree, TagSubmodule, TagIndexV2, TagIndexExtTree},
 	URL:          "https://gitlab.com/go-git-fixtures/sha256-submodule.git",
 	WorktreeHash: "df8b2731b8978b5efca95cc2eef5ee43915e0945e3310653b0ef0c00453d67c4",
 	ObjectFormat: objectFormatSHA256,
+}, {
+	Name: "midx",
+	Tags: []Tag{
+		TagDotGit, TagMultiPackfile, TagMultiPackIndex, TagMIDXV1, TagIndexV2,
+		TagIndexExtTree,
+	},
+	Head:         "e4bd5b0d0628ce07636454015cf4c7c8fe3eda56",
+	DotGitHash:   "9796b2f5d699a996e418ce3d43e14f819ce39133",
+	ObjectFormat: objectFormatSHA1,
+}, {
+	Name: "midx-bitmap",
+	Tags: []Tag{
+		TagDotGit, TagMultiPackfile, TagMultiPackIndex, TagMIDXV1, TagMIDXRIDX,
+		TagMIDXBitmap, TagIndexV2, TagIndexExtTree,
+	},
+	Head:         "e4bd5b0d0628ce07636454015cf4c7c8fe3eda56",
+	DotGitHash:   "7cdeabb9642835c4f003aa38e6cfbe8c316d2e89",
+	ObjectFormat: objectFormatSHA1,
+}, {
+	Name: "sha256-midx",
+	Tags: []Tag{
+		TagDotGit, TagMultiPackfile, TagMultiPackIndex, TagMIDXV1, TagIndexV2,
+		TagIndexExtTree,
+	},
+	Head:         "cc0fb4a54bc347ee8ce7ce52f14536fb5c677ae3af038b062bb98be4d8a33e70",
+	DotGitHash:   "827d08501b81099c1db24f4b309b90df149ab0a2",
+	ObjectFormat: objectFormatSHA256,
+}, {
+	Name: "sha256-midx-bitmap",
+	Tags: []Tag{
+		TagDotGit, TagMultiPackfile, TagMultiPackIndex, TagMIDXV1, TagMIDXRIDX,
+		TagMIDXBitmap, TagIndexV2, TagIndexExtTree,
+	},
+	Head:         "cc0fb4a54bc347ee8ce7ce52f14536fb5c677ae3af038b062bb98be4d8a33e70",
+	DotGitHash:   "f5b4ce165630126595cfea921c50a7b4f932f4f8",
+	ObjectFormat: objectFormatSHA256,
+}, {
+	Name:         "symlinks",
+	Tags:         []Tag{TagWorktree, TagSymlinks, TagHardlinks, TagExecutable, TagIndexV2, TagIndexExtTree},
+	Head:         "fba8337cd0c58c7d516ba493978af61cf4e59652",
+	WorktreeHash: "8be1f59f7aa3fc70ea4ff9f8fdb49b2c977222eb",
+	ObjectFormat: objectFormatSHA1,
 }}
 
 func All() Fixtures {
@@ -319,14 +417,19 @@ func All() Fixtures {
 
 func Basic() Fixtures {
 	return ByURL(basicGitURL).
-		Exclude("single-branch")
+		Exclude(TagSingleBranch)
 }
 
 func ByURL(url string) Fixtures {
 	return fixtures.ByURL(url)
 }
 
-func ByTag(tag string) Fixtures {
+// ByName returns the fixture with the given Name, or nil if there is none.
+func ByName(name string) *Fixture {
+	return fixtures.ByName(name)
+}
+
+func ByTag(tag Tag) Fixtures {
 	return fixtures.ByTag(tag)
 }
 
@@ -339,10 +442,13 @@ func ByObjectFormat(format string) Fixtures {
 // provide access to some of its files, such as packfile, index, and/or .git
 // directory contents.
 type Fixture struct {
+	// Name uniquely identifies the fixture, e.g. "basic-ofs-delta". It is
+	// used as the subtest name by Fixtures.Run.
+	Name string
 	// URL is the original repository URL from which this fixture was created.
 	URL string
-	// Tags are labels used to categorize and filter fixtures (e.g., tagPackfile, ".git", "worktree").
-	Tags []string
+	// Tags are labels used to categorize and filter fixtures (e.g., TagPackfile, TagDotGit, TagWorktree).
+	Tags []Tag
 	// Head is the commit hash that HEAD points to in this fixture.
 	Head string
 	// PackfileHash is the hash identifier for the fixture's packfile data.
@@ -355,41 +461,63 @@ type Fixture struct {
 	ObjectsCount int32
 	// ObjectFormat specifies the object hash algorithm (e.g., "sha1" or "sha256").
 	ObjectFormat string
+
+	// dependencies are the names of the fixtures this one requires, see
+	// Dependencies.
+	dependencies []string
+
+	// fs holds the data files of fixtures generated at runtime, laid out
+	// like Filesystem. When nil, the embedded Filesystem is used.
+	fs billy.Filesystem
+	// objectBytes is the size of the objects of fixtures generated at
+	// runtime, see Size.
+	objectBytes int64
+	// largeOffsetThreshold is the lowest offset written to the large offset
+	// table of the idx file of fixtures generated at runtime, see
+	// WithLargeOffsetThreshold. Zero means idxfile.LargeOffsetThreshold.
+	largeOffsetThreshold int64
 }
 
-func (f *Fixture) Is(tag string) bool {
+func (f *Fixture) Is(tag Tag) bool {
+	checkTag(tag)
+
 	return slices.Contains(f.Tags, tag)
 }
 
-func (f *Fixture) Packfile() (billy.File, error) {
-	file, err := Filesystem.Open(fmt.Sprintf("data/pack-%s.pack", f.PackfileHash))
-	if err != nil {
-		return nil, fmt.Errorf("%w: %w", os.ErrNotExist, err)
-	}
-
-	return file, nil
+// Packfile opens the fixture's packfile. Of opts, only WithFaults applies.
+func (f *Fixture) Packfile(opts ...Option) (billy.File, error) {
+	return f.openFile(f.packfilePath("pack"), opts)
 }
 
-func (f *Fixture) Idx() (billy.File, error) {
-	file, err := Filesystem.Open(fmt.Sprintf("data/pack-%s.idx", f.PackfileHash))
-	if err != nil {
-		return nil, fmt.Errorf("%w: %w", os.ErrNotExist, err)
-	}
+// Idx opens the fixture's idx file. Of opts, only WithFaults applies.
+func (f *Fixture) Idx(opts ...Option) (billy.File, error) {
+	return f.openFile(f.packfilePath("idx"), opts)
+}
 
-	return file, nil
+// Rev opens the fixture's rev file. Of opts, only WithFaults applies.
+func (f *Fixture) Rev(opts ...Option) (billy.File, error) {
+	return f.openFile(f.packfilePath("rev"), opts)
 }
 
-func (f *Fixture) Rev() (billy.File, error) {
-	file, err := Filesystem.Open(fmt.Sprintf("data/pack-%s.rev", f.PackfileHash))
+// openFile opens the data file name, wrapped as set by WithFaults.
+func (f *Fixture) openFile(name string, opts []Option) (billy.File, error) {
+	o := newOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	file, err := f.filesystem().Open(name)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", os.ErrNotExist, err)
 	}
 
-	return file, nil
+	return o.withFileFaults(file, name), nil
 }
 
 // DotGit creates a new temporary directory and unpacks the repository .git
 // directory into it. Multiple calls to DotGit returns different directories.
+// It fails with ErrModTimeUnsupported if modification times are requested on
+// memfs, see WithArchiveModTimes.
 func (f *Fixture) DotGit(opts ...Option) (billy.Filesystem, error) {
 	o := newOptions()
 	for _, opt := range opts {
@@ -405,26 +533,17 @@ func (f *Fixture) DotGit(opts ...Option) (billy.Filesystem, error) {
 		return fs.Chroot(".git")
 	}
 
-	file, err := Filesystem.Open(fmt.Sprintf("data/git-%s.tgz", f.DotGitHash))
-	if err != nil {
-		return nil, err
-	}
-
-	fs, err := o.fsFactory()
+	fs, err := o.extract(f.filesystem(), fmt.Sprintf("data/git-%s.tgz", f.DotGitHash))
 	if err != nil {
 		return nil, err
 	}
 
-	err = tgz.Extract(file, fs)
-	if err != nil {
-		return nil, err
-	}
-
-	return fs, nil
+	return o.wrap(fs)
 }
 
 func (f *Fixture) Clone() *Fixture {
 	nf := &Fixture{
+		Name:         f.Name,
 		URL:          f.URL,
 		DotGitHash:   f.DotGitHash,
 		Head:         f.Head,
@@ -433,11 +552,38 @@ func (f *Fixture) Clone() *Fixture {
 		ObjectsCount: f.ObjectsCount,
 		Tags:         slices.Clone(f.Tags),
 		ObjectFormat: f.ObjectFormat,
+
+		dependencies: slices.Clone(f.dependencies),
+		fs:           f.fs,
+		objectBytes:  f.objectBytes,
+
+		largeOffsetThreshold: f.largeOffsetThreshold,
 	}
 
 	return nf
 }
 
+func (f *Fixture) filesystem() billy.Filesystem {
+	if f.fs != nil {
+		return f.fs
+	}
+
+	return Filesystem
+}
+
+// dotGitArchive returns the path of the archive holding the fixture's .git
+// directory, or an empty string if it has none.
+func (f *Fixture) dotGitArchive() string {
+	switch {
+	case f.DotGitHash != "":
+		return fmt.Sprintf("data/git-%s.tgz", f.DotGitHash)
+	case f.WorktreeHash != "":
+		return fmt.Sprintf("data/worktree-%s.tgz", f.WorktreeHash)
+	}
+
+	return ""
+}
+
 // EnsureIsBare overrides the config file with one where bare is true.
 func EnsureIsBare(fs billy.Filesystem) error {
 	_, err := fs.Stat("config")
@@ -466,39 +612,33 @@ func EnsureIsBare(fs billy.Filesystem) error {
 	return err
 }
 
+// Worktree unpacks the fixture's worktree, including its .git directory, into
+// a new filesystem. As with DotGit, each call gets its own copy, and it fails
+// with ErrModTimeUnsupported if modification times are requested on memfs.
 func (f *Fixture) Worktree(opts ...Option) (billy.Filesystem, error) {
 	o := newOptions()
 	for _, opt := range opts {
 		opt(o)
 	}
 
-	file, err := Filesystem.Open(fmt.Sprintf("data/worktree-%s.tgz", f.WorktreeHash))
-	if err != nil {
-		return nil, err
-	}
-
-	fs, err := o.fsFactory()
+	fs, err := o.extract(f.filesystem(), fmt.Sprintf("data/worktree-%s.tgz", f.WorktreeHash))
 	if err != nil {
 		return nil, err
 	}
 
-	err = tgz.Extract(file, fs)
-	if err != nil {
-		return nil, err
-	}
-
-	return fs, nil
+	return o.wrap(fs)
 }
 
 type Fixtures []*Fixture
 
-// Run calls test within a t.Run for each fixture in g.
+// Run calls test within a t.Run for each fixture in g, named after the
+// fixture's Name, so that a single fixture can be run with e.g.
+// -run 'TestX/basic-ofs-delta$'. See RunWith for more options.
 func (g Fixtures) Run(t *testing.T, test func(*testing.T, *Fixture)) {
 	t.Helper()
 
 	for _, f := range g {
-		name := fmt.Sprintf("fixture run (%q, %q)", f.URL, f.Tags)
-		t.Run(name, func(t *testing.T) {
+		t.Run(f.runName(), func(t *testing.T) {
 			test(t, f)
 		})
 	}
@@ -512,7 +652,21 @@ func (g Fixtures) One() *Fixture {
 	return g[0].Clone()
 }
 
-func (g Fixtures) ByTag(tag string) Fixtures {
+// ByName returns the fixture of g with the given Name, or nil if there is
+// none.
+func (g Fixtures) ByName(name string) *Fixture {
+	for _, f := range g {
+		if f.Name == name {
+			return f.Clone()
+		}
+	}
+
+	return nil
+}
+
+func (g Fixtures) ByTag(tag Tag) Fixtures {
+	checkTag(tag)
+
 	r := make(Fixtures, 0, len(g))
 	for _, f := range g {
 		if f.Is(tag) {
@@ -545,7 +699,9 @@ func (g Fixtures) ByURL(url string) Fixtures {
 	return r
 }
 
-func (g Fixtures) Exclude(tag string) Fixtures {
+func (g Fixtures) Exclude(tag Tag) Fixtures {
+	checkTag(tag)
+
 	r := make(Fixtures, 0, len(g))
 	for _, f := range g {
 		if !f.Is(tag) {
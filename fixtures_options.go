@@ -1,27 +1,159 @@
 package fixtures
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
 	"github.com/go-git/go-billy/v6"
 	"github.com/go-git/go-billy/v6/osfs"
+	"github.com/go-git/go-git-fixtures/v6/internal/diskcache"
+	"github.com/go-git/go-git-fixtures/v6/internal/overlay"
 	"github.com/go-git/go-git-fixtures/v6/internal/tgz"
 )
 
+// CacheDirEnv is the environment variable setting the default cache
+// directory, see WithCacheDir.
+const CacheDirEnv = "GO_GIT_FIXTURES_CACHE"
+
+// ErrModTimeUnsupported is returned when WithArchiveModTimes or
+// WithFixedModTime are used on a filesystem which cannot hold modification
+// times, such as the default memfs.
+var ErrModTimeUnsupported = errors.New("modification times not supported")
+
 type Option func(*options)
 
 type options struct {
 	fsFactory func() (billy.Filesystem, error)
+	modTime   func(time.Time) time.Time
+	// overlay is whether to return a copy-on-write overlay of the cached
+	// extraction instead of extracting into a new fs from fsFactory.
+	overlay bool
+	// cacheDir is the on-disk cache used for filesystems not using an
+	// overlay, disabled if empty.
+	cacheDir string
+	// faults are the rules of the faults injected in the returned
+	// filesystem, see WithFaults.
+	faults    []FaultRule
+	faultSeed uint64
+	// readOnly is whether to return a read-only view, see WithReadOnly.
+	readOnly bool
+	// restrictPermissions, if set, is the test at the end of which the
+	// write permissions removed from extracted files are restored, see
+	// WithRestrictedPermissions.
+	restrictPermissions testing.TB
 }
 
 func newOptions() *options {
 	return &options{
 		fsFactory: tgz.MemFactory,
+		modTime:   nil,
+		overlay:   true,
+		cacheDir:  os.Getenv(CacheDirEnv),
+		faults:    nil,
+		faultSeed: 0,
+
+		readOnly:            false,
+		restrictPermissions: nil,
+	}
+}
+
+// extract returns the contents of the archive name, read from src. Archives
+// are only decompressed once per process, see archiveCache, or once per
+// cache directory if one is set.
+func (o *options) extract(src billy.Filesystem, name string) (billy.Filesystem, error) {
+	if !o.overlay && o.cacheDir != "" {
+		return o.copyFromCacheDir(src, name)
+	}
+
+	// The overlay shares a memfs base, which has no way to set times.
+	if o.overlay && o.modTime != nil {
+		return nil, fmt.Errorf("%w on memfs, use WithTargetDir", ErrModTimeUnsupported)
+	}
+
+	a, err := archiveCache.get(name, func() (*cachedArchive, error) {
+		return loadArchive(src, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if o.overlay {
+		return overlay.New(a.base), nil
 	}
+
+	fs, err := o.fsFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tgz.ExtractTar(bytes.NewReader(a.tar), fs, o.tgzOptions()...)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs, nil
+}
+
+// copyFromCacheDir extracts the archive name into the cache directory, unless
+// already there, and copies it into a new fs from fsFactory.
+func (o *options) copyFromCacheDir(src billy.Filesystem, name string) (billy.Filesystem, error) {
+	stamp, err := archiveStamp(src, name)
+	if err != nil {
+		return nil, err
+	}
+
+	key := strings.TrimSuffix(path.Base(name), ".tgz")
+
+	cached, err := diskcache.New(o.cacheDir).Get(key, stamp, func(fs billy.Filesystem) error {
+		file, err := src.Open(name)
+		if err != nil {
+			return err
+		}
+
+		// The archived times are kept, so they can be restored on copy.
+		return tgz.Extract(file, fs, tgz.WithModTime(func(t time.Time) time.Time { return t }))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fs, err := o.fsFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tgz.Copy(cached, fs, o.tgzOptions()...)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs, nil
+}
+
+func (o *options) tgzOptions() []tgz.Option {
+	if o.modTime == nil {
+		return nil
+	}
+
+	return []tgz.Option{tgz.WithModTime(o.modTime)}
 }
 
 // WithMemFS returns the option of using memfs for the fs created for Fixtures.
+// This is the default.
+//
+// Archives are extracted once per process, and each call gets an in-memory
+// copy-on-write overlay of that extraction, so changes are not visible to
+// other calls.
 func WithMemFS() Option {
 	return func(o *options) {
 		o.fsFactory = tgz.MemFactory
+		o.overlay = true
 	}
 }
 
@@ -33,10 +165,52 @@ func WithMemFS() Option {
 // to delegate that to the testing framework:
 //
 //	WithTargetDir(t.TempDir)
+//
+// Each call writes a full copy of the fixture into its target dir, so it can
+// be used by tools outside of billy, such as the git CLI.
 func WithTargetDir(dirName func() string, opts ...osfs.Option) Option {
 	return func(o *options) {
+		o.overlay = false
 		o.fsFactory = func() (billy.Filesystem, error) {
 			return osfs.New(dirName(), opts...), nil
 		}
 	}
 }
+
+// WithArchiveModTimes returns the option of restoring the modification times
+// recorded in the fixture archive, instead of leaving files with the time
+// they were extracted at. This makes stat-based change detection, such as the
+// racy-git checks against the index, reproducible.
+//
+// Times can only be restored on OS-based filesystems (see WithTargetDir) and
+// on those implementing billy.Change. Combined with memfs, the default,
+// DotGit and Worktree fail with ErrModTimeUnsupported.
+func WithArchiveModTimes() Option {
+	return func(o *options) {
+		o.modTime = func(t time.Time) time.Time { return t }
+	}
+}
+
+// WithFixedModTime returns the option of setting the modification time of
+// every extracted file and directory to t. See WithArchiveModTimes for the
+// filesystems it applies to.
+func WithFixedModTime(t time.Time) Option {
+	return func(o *options) {
+		o.modTime = func(time.Time) time.Time { return t }
+	}
+}
+
+// WithCacheDir returns the option of using dir as a cache of extracted
+// archives, shared across processes such as the test binaries of a go test
+// run. Archives are extracted into it once, and filesystems created by
+// WithTargetDir are copied from it instead of extracted. It has no effect
+// on memfs, which is cached in memory.
+//
+// It defaults to the value of the GO_GIT_FIXTURES_CACHE environment variable.
+// An empty dir disables the cache. Entries are never removed, so dir should
+// be somewhere like a subdirectory of os.UserCacheDir.
+func WithCacheDir(dir string) Option {
+	return func(o *options) {
+		o.cacheDir = dir
+	}
+}
@@ -2,11 +2,18 @@ package fixtures_test
 
 import (
 	"io"
+	"os"
+	"path/filepath"
+	"regexp"
 	"slices"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-git/go-billy/v6"
 	"github.com/go-git/go-billy/v6/osfs"
+	"github.com/go-git/go-billy/v6/util"
 	fixtures "github.com/go-git/go-git-fixtures/v6"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -81,30 +88,31 @@ func TestAll(t *testing.T) {
 
 	fs := fixtures.All()
 
-	assert.Len(t, fs, 42)
+	assert.Len(t, fs, 47)
 }
 
 func TestByTag(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		tag string
+		tag fixtures.Tag
 		len int
 	}{
 		{tag: "packfile", len: 22},
 		{tag: "ofs-delta", len: 3},
-		{tag: ".git", len: 15},
+		{tag: ".git", len: 19},
 		{tag: "merge-conflict", len: 1},
-		{tag: "worktree", len: 7},
+		{tag: "worktree", len: 8},
 		{tag: "submodule", len: 2},
 		{tag: "tags", len: 1},
 		{tag: "notes", len: 1},
-		{tag: "multi-packfile", len: 1},
+		{tag: "multi-packfile", len: 5},
+		{tag: "multi-pack-index", len: 4},
 		{tag: "diff-tree", len: 7},
 	}
 
 	for _, tc := range tests {
-		t.Run(tc.tag, func(t *testing.T) {
+		t.Run(string(tc.tag), func(t *testing.T) {
 			t.Parallel()
 
 			f := fixtures.ByTag(tc.tag)
@@ -148,6 +156,56 @@ func TestByURL(t *testing.T) {
 	}
 }
 
+func TestNames(t *testing.T) {
+	t.Parallel()
+
+	valid := regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	seen := map[string]bool{}
+
+	for _, f := range fixtures.All() {
+		assert.Regexp(t, valid, f.Name)
+		assert.False(t, seen[f.Name], "duplicate name %q", f.Name)
+		seen[f.Name] = true
+	}
+}
+
+func TestByName(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByName("basic-ofs-delta")
+	require.NotNil(t, f)
+	assert.Equal(t, "basic-ofs-delta", f.Name)
+	assert.Equal(t, "https://github.com/git-fixtures/basic.git", f.URL)
+	assert.True(t, f.Is(fixtures.TagOFSDelta))
+
+	f = fixtures.ByName("sha256-basic")
+	require.NotNil(t, f)
+	assert.Equal(t, "sha256", f.ObjectFormat)
+
+	assert.Nil(t, fixtures.ByName("missing"))
+	assert.Nil(t, fixtures.ByTag(fixtures.TagWorktree).ByName("basic-ofs-delta"))
+
+	for _, f := range fixtures.All() {
+		assert.Equal(t, f, fixtures.ByName(f.Name))
+	}
+}
+
+func TestRunNames(t *testing.T) {
+	t.Parallel()
+
+	var names []string
+
+	fixtures.Basic().ByTag(fixtures.TagPackfile).Run(t, func(t *testing.T, _ *fixtures.Fixture) {
+		names = append(names, t.Name())
+	})
+
+	assert.Equal(t, []string{
+		"TestRunNames/basic-ofs-delta",
+		"TestRunNames/basic-ref-delta",
+		"TestRunNames/sha256-basic",
+	}, names)
+}
+
 func TestIdx(t *testing.T) {
 	t.Parallel()
 
@@ -229,30 +287,30 @@ func TestByObjectFormat(t *testing.T) {
 	tests := []struct {
 		name         string
 		objectFormat string
-		tag          string
+		tag          fixtures.Tag
 		expectedLen  int
 	}{
 		{
 			name:         "sha1",
 			objectFormat: "sha1",
-			expectedLen:  38,
+			expectedLen:  41,
 		},
 		{
 			name:         "sha256",
 			objectFormat: "sha256",
-			expectedLen:  4,
+			expectedLen:  6,
 		},
 		{
 			name:         "sha1 with .git tag",
 			objectFormat: "sha1",
 			tag:          ".git",
-			expectedLen:  13,
+			expectedLen:  15,
 		},
 		{
 			name:         "sha256 with .git tag",
 			objectFormat: "sha256",
 			tag:          ".git",
-			expectedLen:  2,
+			expectedLen:  4,
 		},
 		{
 			name:         "sha1 with packfile tag",
@@ -307,3 +365,170 @@ func TestEnsureIsBare(t *testing.T) {
 
 	assert.Contains(t, string(content), "bare = true")
 }
+
+func TestWorktreeSymlinks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		option fixtures.Option
+	}{
+		{name: "memfs", option: fixtures.WithMemFS()},
+		{name: "osfs", option: fixtures.WithTargetDir(t.TempDir, osfs.WithBoundOS())},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := fixtures.ByTag("symlinks").One()
+			require.NotNil(t, f)
+
+			fs, err := f.Worktree(tc.option)
+			require.NoError(t, err)
+
+			sfs, ok := fs.(billy.Symlink)
+			require.True(t, ok)
+
+			target, err := sfs.Readlink("link-to-readme")
+			require.NoError(t, err)
+			assert.Equal(t, "README", target)
+
+			target, err = sfs.Readlink("broken-link")
+			require.NoError(t, err)
+			assert.Equal(t, "does-not-exist", target)
+
+			fi, err := sfs.Lstat("link-to-dir")
+			require.NoError(t, err)
+			assert.Equal(t, os.ModeSymlink, fi.Mode().Type())
+
+			fi, err = fs.Stat("run.sh")
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
+
+			readme, err := util.ReadFile(fs, "README")
+			require.NoError(t, err)
+
+			hardlink, err := util.ReadFile(fs, "hardlink-to-readme")
+			require.NoError(t, err)
+			assert.Equal(t, readme, hardlink)
+		})
+	}
+}
+
+func TestWithModTime(t *testing.T) {
+	t.Parallel()
+
+	fixed := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	f := fixtures.ByTag("worktree").One()
+	require.NotNil(t, f)
+
+	fs, err := f.Worktree(fixtures.WithTargetDir(t.TempDir), fixtures.WithFixedModTime(fixed))
+	require.NoError(t, err)
+
+	err = util.Walk(fs, "", func(path string, fi os.FileInfo, err error) error {
+		require.NoError(t, err)
+
+		// The root directory is not part of every archive.
+		if path != "" && fi.Mode()&os.ModeSymlink == 0 {
+			assert.True(t, fixed.Equal(fi.ModTime()), "%s: %v", path, fi.ModTime())
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	fs, err = f.Worktree(fixtures.WithTargetDir(t.TempDir), fixtures.WithArchiveModTimes())
+	require.NoError(t, err)
+
+	first, err := fs.Stat(".git/HEAD")
+	require.NoError(t, err)
+
+	fs, err = f.Worktree(fixtures.WithTargetDir(t.TempDir), fixtures.WithArchiveModTimes())
+	require.NoError(t, err)
+
+	second, err := fs.Stat(".git/HEAD")
+	require.NoError(t, err)
+	assert.True(t, first.ModTime().Equal(second.ModTime()))
+	assert.True(t, first.ModTime().Before(fixed))
+
+	_, err = f.Worktree(fixtures.WithArchiveModTimes())
+	require.ErrorIs(t, err, fixtures.ErrModTimeUnsupported)
+
+	_, err = fixtures.Basic().One().DotGit(fixtures.WithMemFS(), fixtures.WithFixedModTime(fixed))
+	require.ErrorIs(t, err, fixtures.ErrModTimeUnsupported)
+}
+
+func TestDotGitIsolated(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.Basic().One()
+	require.NotNil(t, f)
+
+	for range 4 {
+		t.Run("", func(t *testing.T) {
+			t.Parallel()
+
+			fs, err := f.DotGit()
+			require.NoError(t, err)
+
+			original, err := util.ReadFile(fs, "HEAD")
+			require.NoError(t, err)
+			assert.NotEqual(t, "changed", string(original))
+
+			require.NoError(t, util.WriteFile(fs, "HEAD", []byte("changed"), 0o644))
+			require.NoError(t, util.RemoveAll(fs, "refs"))
+
+			content, err := util.ReadFile(fs, "HEAD")
+			require.NoError(t, err)
+			assert.Equal(t, "changed", string(content))
+
+			_, err = fs.Stat("refs")
+			require.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
+
+func TestWithCacheDir(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+
+	f := fixtures.ByTag("symlinks").One()
+	require.NotNil(t, f)
+
+	for range 2 {
+		fs, err := f.Worktree(fixtures.WithTargetDir(t.TempDir), fixtures.WithCacheDir(cacheDir))
+		require.NoError(t, err)
+
+		content, err := util.ReadFile(fs, "hardlink-to-readme")
+		require.NoError(t, err)
+		assert.NotEmpty(t, content)
+
+		target, err := fs.Readlink("link-to-readme")
+		require.NoError(t, err)
+		assert.Equal(t, "README", target)
+
+		fi, err := fs.Stat("run.sh")
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
+
+		// Changes to the copy do not reach the cache.
+		require.NoError(t, util.WriteFile(fs, "README", []byte("changed"), 0o644))
+	}
+
+	key := "worktree-" + f.WorktreeHash
+
+	stamp, err := os.ReadFile(filepath.Join(cacheDir, key+".stamp"))
+	require.NoError(t, err)
+	assert.Contains(t, string(stamp), "sha256:")
+
+	// The stamp names the directory of the entry on its second line.
+	lines := strings.SplitN(string(stamp), "\n", 3)
+	require.Len(t, lines, 3)
+
+	content, err := os.ReadFile(filepath.Join(cacheDir, lines[1], "README"))
+	require.NoError(t, err)
+	assert.NotEqual(t, "changed", string(content))
+}
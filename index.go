@@ -0,0 +1,174 @@
+package fixtures
+
+import (
+	"slices"
+
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git-fixtures/v6/internal/index"
+)
+
+//nolint:gochecknoglobals
+var indexCache = newMetadataCache[*Index]()
+
+// IndexEntry is the expected decoding of a single entry of a fixture's index
+// file. Fields use plain types so go-git-fixtures does not depend on go-git.
+type IndexEntry struct {
+	// Path is the slash-separated path of the entry, relative to the
+	// worktree root.
+	Path string
+	// Mode is the file mode, e.g. 0o100644 or 0o160000 for submodules.
+	Mode uint32
+	// Stage is the merge stage: 0 for regular entries, 1 to 3 for the base,
+	// ours and theirs sides of a conflict.
+	Stage int
+	// Hash is the hex-encoded object hash.
+	Hash string
+	// AssumeValid is the assume-valid (assume-unchanged) flag.
+	AssumeValid bool
+	// SkipWorktree is the skip-worktree extended flag.
+	SkipWorktree bool
+	// IntentToAdd is the intent-to-add extended flag, set by git add -N.
+	IntentToAdd bool
+}
+
+// IndexTree is a node of the cached tree (TREE) extension.
+type IndexTree struct {
+	// Path is the full slash-separated path of the directory, empty for
+	// the root tree.
+	Path string
+	// Entries is the number of index entries covered by the tree, or -1 if
+	// it has been invalidated.
+	Entries int
+	// Subtrees is the number of direct subtrees.
+	Subtrees int
+	// Hash is the hex-encoded tree hash, empty if invalidated.
+	Hash string
+}
+
+// IndexResolveUndo is an entry of the resolve undo (REUC) extension, holding
+// the stages 1 to 3 of a path whose conflict was resolved. Modes and Hashes
+// are indexed by stage minus one; missing stages have a zero mode and an
+// empty hash.
+type IndexResolveUndo struct {
+	Path   string
+	Modes  [3]uint32
+	Hashes [3]string
+}
+
+// IndexEndOfEntries is the end of index entries (EOIE) extension.
+type IndexEndOfEntries struct {
+	// Offset is the offset of the first extension in the index file.
+	Offset uint32
+	// Hash is the hex-encoded hash over the extension headers.
+	Hash string
+}
+
+// Index is the expected decoding of a fixture's index file.
+type Index struct {
+	// Version is the index format version, from 2 to 4.
+	Version int
+	// Entries are the index entries, in index order.
+	Entries []IndexEntry
+	// Extensions are the signatures of the extensions present in the index,
+	// in file order (e.g. "TREE", "REUC", "EOIE").
+	Extensions []string
+	// Tree holds the nodes of the TREE extension, in pre-order.
+	Tree []IndexTree
+	// ResolveUndo holds the entries of the REUC extension.
+	ResolveUndo []IndexResolveUndo
+	// EndOfEntries is the EOIE extension, nil if absent.
+	EndOfEntries *IndexEndOfEntries
+}
+
+// Index returns the decoded index file of the fixture's .git directory.
+//
+// The index is decoded on first use and cached. Returns nil if the fixture
+// has no .git directory or no index file.
+func (f *Fixture) Index() *Index {
+	name := f.dotGitArchive()
+	if name == "" {
+		return nil
+	}
+
+	idx, err := indexCache.get(name, func() (*Index, error) {
+		fs, err := f.DotGit()
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := util.ReadFile(fs, "index")
+		if err != nil {
+			return nil, err
+		}
+
+		decoded, err := index.Decode(f.ObjectFormat, data)
+		if err != nil {
+			return nil, err
+		}
+
+		return newIndex(decoded), nil
+	})
+	if err != nil {
+		return nil
+	}
+
+	return idx.clone()
+}
+
+// IndexEntries returns the entries of the fixture's index file, in index
+// order. See Index for its extensions.
+//
+// Returns nil if the fixture has no .git directory or no index file.
+func (f *Fixture) IndexEntries() []IndexEntry {
+	idx := f.Index()
+	if idx == nil {
+		return nil
+	}
+
+	return idx.Entries
+}
+
+func newIndex(decoded *index.Index) *Index {
+	idx := &Index{
+		Version:      decoded.Version,
+		Entries:      make([]IndexEntry, 0, len(decoded.Entries)),
+		Extensions:   decoded.Extensions,
+		Tree:         nil,
+		ResolveUndo:  nil,
+		EndOfEntries: nil,
+	}
+
+	for _, e := range decoded.Entries {
+		idx.Entries = append(idx.Entries, IndexEntry(e))
+	}
+
+	for _, t := range decoded.Tree {
+		idx.Tree = append(idx.Tree, IndexTree(t))
+	}
+
+	for _, r := range decoded.ResolveUndo {
+		idx.ResolveUndo = append(idx.ResolveUndo, IndexResolveUndo(r))
+	}
+
+	if decoded.EndOfIndexEntries != nil {
+		eoie := IndexEndOfEntries(*decoded.EndOfIndexEntries)
+		idx.EndOfEntries = &eoie
+	}
+
+	return idx
+}
+
+func (idx *Index) clone() *Index {
+	c := *idx
+	c.Entries = slices.Clone(idx.Entries)
+	c.Extensions = slices.Clone(idx.Extensions)
+	c.Tree = slices.Clone(idx.Tree)
+	c.ResolveUndo = slices.Clone(idx.ResolveUndo)
+
+	if idx.EndOfEntries != nil {
+		eoie := *idx.EndOfEntries
+		c.EndOfEntries = &eoie
+	}
+
+	return &c
+}
@@ -0,0 +1,125 @@
+package fixtures_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexMatchesTags(t *testing.T) {
+	t.Parallel()
+
+	extensions := map[fixtures.Tag]string{
+		"index-ext-tree": "TREE",
+		"index-ext-reuc": "REUC",
+		"index-ext-eoie": "EOIE",
+	}
+
+	for _, f := range fixtures.All() {
+		idx := f.Index()
+		if idx == nil {
+			continue
+		}
+
+		assert.True(t, f.Is(fixtures.Tag(fmt.Sprintf("index-v%d", idx.Version))), "%v: index v%d", f.Tags, idx.Version)
+
+		for tag, sig := range extensions {
+			assert.Equal(t, f.Is(tag), slices.Contains(idx.Extensions, sig), "%v: %s", f.Tags, sig)
+		}
+
+		if f.Is("index-ext-none") {
+			assert.Empty(t, idx.Extensions)
+		}
+
+		assert.Equal(t, f.Is("intent-to-add"), slices.ContainsFunc(idx.Entries, func(e fixtures.IndexEntry) bool {
+			return e.IntentToAdd
+		}), f.Tags)
+	}
+}
+
+func TestIndexEntries(t *testing.T) {
+	t.Parallel()
+
+	entries := fixtures.ByTag("index-v4").One().IndexEntries()
+	require.Len(t, entries, 11)
+	assert.Equal(t, fixtures.IndexEntry{
+		Path:         ".gitignore",
+		Mode:         0o100644,
+		Stage:        0,
+		Hash:         "32858aad3c383ed1ff0a0f9bdf231d54a00c9e88",
+		AssumeValid:  false,
+		SkipWorktree: false,
+		IntentToAdd:  false,
+	}, entries[0])
+
+	// Version 4 prefix-compresses paths, which must decode to the same
+	// entries as the version 3 fixture holding the same tree.
+	assert.Equal(t, fixtures.ByTag("index-v3").One().IndexEntries(), entries)
+}
+
+func TestIndexMergeConflict(t *testing.T) {
+	t.Parallel()
+
+	var stages []int
+
+	for _, e := range fixtures.ByTag("merge-conflict").One().IndexEntries() {
+		if e.Path == "go/example.go" {
+			stages = append(stages, e.Stage)
+		}
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, stages)
+}
+
+func TestIndexExtensions(t *testing.T) {
+	t.Parallel()
+
+	idx := fixtures.ByTag("resolve-undo").One().Index()
+	require.NotNil(t, idx)
+	assert.Equal(t, []fixtures.IndexResolveUndo{{
+		Path:  "go/example.go",
+		Modes: [3]uint32{0o100644, 0o100644, 0o100644},
+		Hashes: [3]string{
+			"880cd14280f4b9b6ed3986d6671f907d7cc2a198",
+			"d499a1a0b79b7d87a35155afd0c1cce78b37a91c",
+			"14f8e368114f561c38e134f6e68ea6fea12d77ed",
+		},
+	}, {
+		Path:  "haskal/haskal.hs",
+		Modes: [3]uint32{0, 0o100644, 0o100644},
+		Hashes: [3]string{
+			"",
+			"257cc5642cb1a054f08cc83f2d943e56fd3ebe99",
+			"cebf390d0d08dc60d6a669097683a5ff9e5a43de",
+		},
+	}}, idx.ResolveUndo)
+
+	idx = fixtures.ByTag("index-ext-eoie").One().Index()
+	require.NotNil(t, idx)
+	require.NotNil(t, idx.EndOfEntries)
+	assert.Equal(t, uint32(716), idx.EndOfEntries.Offset)
+	assert.Equal(t, "922e89d9ffd7cefce93a211615b2053c0f42bd78", idx.EndOfEntries.Hash)
+
+	idx = fixtures.ByTag("intent-to-add").One().Index()
+	require.NotNil(t, idx)
+	require.NotEmpty(t, idx.Tree)
+	assert.Equal(t, fixtures.IndexTree{Path: "", Entries: -1, Subtrees: 5, Hash: ""}, idx.Tree[0])
+	assert.Equal(t, fixtures.IndexTree{
+		Path:     "json",
+		Entries:  2,
+		Subtrees: 0,
+		Hash:     "5a877e6a906a2743ad6e45d99c1793642aaf8eda",
+	}, idx.Tree[3])
+}
+
+func TestIndexReturnsNilWithoutDotGit(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByTag("codecommit").One()
+	assert.Nil(t, f.Index())
+	assert.Nil(t, f.IndexEntries())
+}
@@ -0,0 +1,481 @@
+// Command genmeta derives metadata, such as references, multi-pack-indexes and
+// object sizes, from the packfiles and the .git and worktree archives under
+// data/ and writes it as Go source into the fixtures package, so it can be
+// used without extracting the archives at test time.
+//
+// Every packfile and archive named by the fixtures catalogue must be present:
+// genmeta fails rather than leave a fixture without its metadata.
+//
+// It is run through go generate from the repository root:
+//
+//	go generate ./...
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go/format"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/memfs"
+	"github.com/go-git/go-billy/v6/osfs"
+	"github.com/go-git/go-billy/v6/util"
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/go-git/go-git-fixtures/v6/internal/dotgit"
+	"github.com/go-git/go-git-fixtures/v6/internal/midxfile"
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+	"github.com/go-git/go-git-fixtures/v6/internal/packfile"
+	"github.com/go-git/go-git-fixtures/v6/internal/tgz"
+)
+
+const (
+	dataDir  = "data"
+	refsFile = "refs_generated.go"
+	midxFile = "midx_generated.go"
+	sizeFile = "size_generated.go"
+
+	midxPath = "objects/pack/multi-pack-index"
+
+	sha256HexSize = 64
+
+	header = "// Code generated by internal/cmd/genmeta. DO NOT EDIT.\n\npackage fixtures\n\n"
+)
+
+var (
+	errNoRefs      = errors.New("no references found")
+	errMissingData = errors.New("missing fixture data")
+)
+
+func main() {
+	err := run(dataDir, ".")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "genmeta:", err)
+		os.Exit(1)
+	}
+}
+
+func run(data, out string) error {
+	list, err := archives(data)
+	if err != nil {
+		return err
+	}
+
+	for name, generate := range generators(data) {
+		src, err := generate(list)
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(filepath.Join(out, name), src, 0o644) //nolint:gosec
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// generators returns the functions generating each file from the archives
+// of the data directory data.
+func generators(data string) map[string]func([]archive) ([]byte, error) {
+	return map[string]func([]archive) ([]byte, error){
+		refsFile: generateRefs,
+		midxFile: generateMIDX,
+		sizeFile: func(list []archive) ([]byte, error) { return generateSizes(data, list) },
+	}
+}
+
+// archive is an extracted .git directory, keyed by the name of the archive
+// it was extracted from, without extension (e.g. git-<hash>).
+type archive struct {
+	key string
+	// fs is the .git directory of the archive, nil for worktree archives
+	// without one at their root.
+	fs billy.Filesystem
+	// root is the whole content of the archive.
+	root billy.Filesystem
+}
+
+// archives extracts every .git and worktree archive of the fixtures
+// catalogue from dir.
+func archives(dir string) ([]archive, error) {
+	src := osfs.New(dir)
+
+	var result []archive
+
+	for _, key := range fixtureKeys(func(f *fixtures.Fixture) []string {
+		var keys []string
+		if f.DotGitHash != "" {
+			keys = append(keys, "git-"+f.DotGitHash)
+		}
+
+		if f.WorktreeHash != "" {
+			keys = append(keys, "worktree-"+f.WorktreeHash)
+		}
+
+		return keys
+	}) {
+		name := key + ".tgz"
+
+		file, err := src.Open(name)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", errMissingData, name)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		fs := memfs.New()
+
+		err = tgz.Extract(file, fs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		a := archive{key: key, fs: fs, root: fs}
+
+		if strings.HasPrefix(key, "worktree-") {
+			a.fs = nil
+
+			fi, err := fs.Stat(".git")
+			if err == nil && fi.IsDir() {
+				a.fs, err = fs.Chroot(".git")
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		result = append(result, a)
+	}
+
+	return result, nil
+}
+
+// fixtureKeys returns the keys returned by keys for every fixture of the
+// catalogue, sorted and without duplicates.
+func fixtureKeys(keys func(*fixtures.Fixture) []string) []string {
+	var result []string
+	for _, f := range fixtures.All() {
+		result = append(result, keys(f)...)
+	}
+
+	slices.Sort(result)
+
+	return slices.Compact(result)
+}
+
+func generateRefs(list []archive) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString(header)
+	buf.WriteString("//nolint:gochecknoglobals\nvar fixtureRefs = map[string][]Reference{\n")
+
+	for _, a := range list {
+		if a.fs == nil {
+			continue
+		}
+
+		refs, err := readRefs(a.fs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a.key, err)
+		}
+
+		fmt.Fprintf(&buf, "\t%q: {\n", a.key)
+
+		for _, r := range refs {
+			fmt.Fprintf(&buf, "\t\t{Name: %q", r.Name)
+			writeField(&buf, "Target", r.Target)
+			writeField(&buf, "SymbolicTarget", r.Symbolic)
+			writeField(&buf, "Peeled", r.Peeled)
+
+			if r.Packed {
+				buf.WriteString(", Packed: true")
+			}
+
+			buf.WriteString("},\n")
+		}
+
+		buf.WriteString("\t},\n")
+	}
+
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
+
+func readRefs(fs billy.Filesystem) ([]dotgit.Ref, error) {
+	cfg, err := dotgit.ReadConfig(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := dotgit.ReadRefs(fs, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(refs) == 0 {
+		return nil, errNoRefs
+	}
+
+	err = dotgit.PeelRefs(refs, dotgit.NewObjectStore(fs, cfg.ObjectFormat))
+	if err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+func writeField(buf *bytes.Buffer, name, value string) {
+	if value != "" {
+		fmt.Fprintf(buf, ", %s: %q", name, value)
+	}
+}
+
+func generateMIDX(list []archive) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString(header)
+	buf.WriteString("//nolint:gochecknoglobals\nvar fixtureMultiPackIndexes = map[string]*MultiPackIndex{\n")
+
+	for _, a := range list {
+		if a.fs == nil {
+			continue
+		}
+
+		data, err := util.ReadFile(a.fs, midxPath)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a.key, err)
+		}
+
+		idx, err := midxfile.Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a.key, err)
+		}
+
+		fmt.Fprintf(&buf, "\t%q: {\n", a.key)
+		fmt.Fprintf(&buf, "\t\tVersion: %d,\n\t\tObjectFormat: %q,\n", idx.Version, idx.ObjectFormat)
+		fmt.Fprintf(&buf, "\t\tChecksum: %q,\n", hex.EncodeToString(idx.Checksum))
+
+		buf.WriteString("\t\tPacks: []string{\n")
+
+		for _, name := range idx.Packs {
+			fmt.Fprintf(&buf, "\t\t\t%q,\n", name)
+		}
+
+		buf.WriteString("\t\t},\n")
+		fmt.Fprintf(&buf, "\t\tPreferredPack: %d,\n", preferredPack(idx))
+
+		buf.WriteString("\t\tChunks: []MIDXChunk{\n")
+
+		for _, c := range idx.Chunks {
+			fmt.Fprintf(&buf, "\t\t\t{ID: %q, Offset: %d, Size: %d},\n", c.ID, c.Offset, c.Size)
+		}
+
+		buf.WriteString("\t\t},\n\t\tObjects: []MIDXObject{\n")
+
+		for _, e := range idx.Entries {
+			fmt.Fprintf(&buf, "\t\t\t{Hash: %q, Pack: %d, Offset: %d},\n", e.Hash, e.Pack, e.Offset)
+		}
+
+		buf.WriteString("\t\t},\n")
+
+		if idx.RevIndex != nil {
+			fmt.Fprintf(&buf, "\t\tReverseIndex: []uint32{%s},\n", joinUint32(idx.RevIndex))
+		}
+
+		bitmap := fmt.Sprintf("multi-pack-index-%x.bitmap", idx.Checksum)
+		if _, err := a.fs.Stat(path.Join(path.Dir(midxPath), bitmap)); err == nil {
+			fmt.Fprintf(&buf, "\t\tBitmap: %q,\n", bitmap)
+		}
+
+		buf.WriteString("\t},\n")
+	}
+
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
+
+// preferredPack returns the pack whose objects come first in the pseudo-pack
+// order of idx, or -1 if idx has no RIDX chunk to tell.
+func preferredPack(idx *midxfile.Index) int {
+	if len(idx.RevIndex) == 0 {
+		return -1
+	}
+
+	return int(idx.Entries[idx.RevIndex[0]].Pack)
+}
+
+func joinUint32(values []uint32) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = strconv.FormatUint(uint64(v), 10)
+	}
+
+	return strings.Join(s, ", ")
+}
+
+// generateSizes records the size of the objects of every packfile of the
+// fixtures catalogue, in the data directory dir, and of every archive, once
+// inflated and with deltas resolved.
+func generateSizes(dir string, list []archive) ([]byte, error) {
+	sizes := map[string]int64{}
+
+	for _, name := range fixtureKeys(func(f *fixtures.Fixture) []string {
+		if f.PackfileHash == "" {
+			return nil
+		}
+
+		return []string{"pack-" + f.PackfileHash}
+	}) {
+		data, err := os.ReadFile(filepath.Join(dir, name+".pack"))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s.pack", errMissingData, name)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		format := object.FormatSHA1
+		if len(strings.TrimPrefix(name, "pack-")) == sha256HexSize {
+			format = object.FormatSHA256
+		}
+
+		size, err := packSize(format, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s.pack: %w", name, err)
+		}
+
+		sizes[name] = size
+	}
+
+	for _, a := range list {
+		size, err := archiveSize(a)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a.key, err)
+		}
+
+		sizes[a.key] = size
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(header)
+	buf.WriteString("//nolint:gochecknoglobals\nvar fixtureObjectBytes = map[string]int64{\n")
+
+	for _, key := range slices.Sorted(maps.Keys(sizes)) {
+		fmt.Fprintf(&buf, "\t%q: %d,\n", key, sizes[key])
+	}
+
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
+
+// packSize returns the size of the objects of the packfile data, whose
+// objects use the given format. Thin packs, whose deltas cannot be resolved,
+// count their deltas as stored.
+func packSize(format string, data []byte) (int64, error) {
+	var size int64
+
+	objects, err := packfile.Decode(format, data)
+	if errors.Is(err, packfile.ErrMissingBase) {
+		raw, err := packfile.ScanRaw(format, data)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, o := range raw {
+			size += o.Size
+		}
+
+		return size, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, o := range objects {
+		size += int64(len(o.Content))
+	}
+
+	return size, nil
+}
+
+// archiveSize returns the size of the objects of the .git directory of a or,
+// if it has none, of every repository within it.
+func archiveSize(a archive) (int64, error) {
+	if a.fs != nil {
+		return dotGitSize(a.fs)
+	}
+
+	var size int64
+
+	err := util.Walk(a.root, "", func(name string, fi os.FileInfo, err error) error {
+		if err != nil || !fi.IsDir() || path.Base(name) != ".git" {
+			return err
+		}
+
+		fs, err := a.root.Chroot(name)
+		if err != nil {
+			return err
+		}
+
+		n, err := dotGitSize(fs)
+		if err != nil {
+			return err
+		}
+
+		size += n
+
+		return filepath.SkipDir
+	})
+
+	return size, err
+}
+
+// dotGitSize returns the size of the objects of the .git directory fs.
+func dotGitSize(fs billy.Filesystem) (int64, error) {
+	cfg, err := dotgit.ReadConfig(fs)
+	if err != nil {
+		return 0, err
+	}
+
+	store := dotgit.NewObjectStore(fs, cfg.ObjectFormat)
+
+	hashes, err := store.Hashes()
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+
+	for _, h := range hashes {
+		_, content, err := store.Get(h)
+		if err != nil {
+			return 0, err
+		}
+
+		size += int64(len(content))
+	}
+
+	return size, nil
+}
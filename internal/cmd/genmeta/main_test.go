@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGeneratedUpToDate ensures the committed metadata matches what the
+// archives under data/ currently yield. Run go generate ./... to fix it.
+func TestGeneratedUpToDate(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join("..", "..", "..")
+
+	data := filepath.Join(root, dataDir)
+
+	list, err := archives(data)
+	require.NoError(t, err)
+
+	for name, generate := range generators(data) {
+		want, err := generate(list)
+		require.NoError(t, err)
+
+		got, err := os.ReadFile(filepath.Join(root, name))
+		require.NoError(t, err)
+
+		assert.Equal(t, string(want), string(got), "%s is stale, run go generate ./...", name)
+	}
+}
@@ -0,0 +1,239 @@
+// Package diskcache implements a directory of extracted archives which can be
+// shared by concurrent processes, such as the test binaries of go test ./...
+//
+// Each version of an entry is a directory named after its key and a unique
+// suffix. A stamp file, named after the key, records what the current version
+// was populated from, its directory, and a manifest of the type and size of
+// every file in it. Entries whose stamp does not match, or whose files no
+// longer match the manifest, e.g. because one was removed or truncated, are
+// populated again. File contents are not hashed, so a change which keeps the
+// size of every file is not detected.
+//
+// Populating an entry is serialised across processes by a lock file. A new
+// version is populated in a new directory, and the stamp is then atomically
+// replaced to point at it, so versions are never modified nor removed once
+// complete and can be read without locking. Superseded versions are left in
+// place until the cache directory is removed.
+package diskcache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/osfs"
+	"github.com/go-git/go-git-fixtures/v6/internal/readonly"
+)
+
+var ErrInvalidKey = errors.New("invalid cache key")
+
+const (
+	dirMode  = 0o755
+	fileMode = 0o644
+
+	lockSuffix    = ".lock"
+	stampSuffix   = ".stamp"
+	tmpSuffix     = ".tmp-"
+	versionSuffix = ".v"
+)
+
+// Cache is a cache directory.
+type Cache struct {
+	root string
+}
+
+// New returns the cache stored in the root directory, which is created on
+// first use.
+func New(root string) *Cache {
+	return &Cache{root: root}
+}
+
+// Get returns a read-only view of the entry key. If the entry is missing,
+// was populated with a different stamp or was modified since, fill is called
+// to populate a new version of it.
+//
+// The stamp should change whenever the contents of the entry would, e.g. by
+// holding a digest of the archive it is extracted from. It must not contain
+// a newline.
+func (c *Cache) Get(key, stamp string, fill func(billy.Filesystem) error) (billy.Filesystem, error) {
+	if key == "" || strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	if dir, ok := c.current(key, stamp); ok {
+		return readonly.New(osfs.New(dir)), nil
+	}
+
+	err := os.MkdirAll(c.root, dirMode)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := osfs.New(c.root).OpenFile(key+lockSuffix, os.O_CREATE|os.O_RDWR, fileMode)
+	if err != nil {
+		return nil, err
+	}
+
+	defer lock.Close()
+
+	locker, ok := lock.(billy.Locker)
+	if !ok {
+		return nil, fmt.Errorf("locking %s: %w", lock.Name(), billy.ErrNotSupported)
+	}
+
+	err = locker.Lock()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = locker.Unlock() }()
+
+	// Another process may have populated the entry while waiting for the
+	// lock.
+	if dir, ok := c.current(key, stamp); ok {
+		return readonly.New(osfs.New(dir)), nil
+	}
+
+	dir, err := c.populate(key, stamp, fill)
+	if err != nil {
+		return nil, fmt.Errorf("populating cache entry %s: %w", key, err)
+	}
+
+	return readonly.New(osfs.New(dir)), nil
+}
+
+// current returns the directory of the current version of the entry key, if
+// it was populated with stamp and still matches its manifest.
+func (c *Cache) current(key, stamp string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(c.root, key+stampSuffix))
+	if err != nil {
+		return "", false
+	}
+
+	rest, ok := strings.CutPrefix(string(data), stamp+"\n")
+	if !ok {
+		return "", false
+	}
+
+	name, recorded, ok := strings.Cut(rest, "\n")
+	if !ok || !strings.HasPrefix(name, key+versionSuffix) || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+
+	dir := filepath.Join(c.root, name)
+
+	current, err := manifest(dir)
+	if err != nil || current != recorded {
+		return "", false
+	}
+
+	return dir, true
+}
+
+// manifest returns the type and size of every file under dir, one per line
+// in lexical order. Symbolic links are listed with their target.
+func manifest(dir string) (string, error) {
+	var b strings.Builder
+
+	err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		switch {
+		case d.IsDir():
+			fmt.Fprintf(&b, "d %s\n", rel)
+		case d.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(name)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(&b, "l %s %s\n", rel, target)
+		default:
+			fi, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(&b, "f %s %d\n", rel, fi.Size())
+		}
+
+		return nil
+	})
+
+	return b.String(), err
+}
+
+// populate fills in a new version of the entry key and makes it current,
+// returning its directory. It must be called with the entry lock held.
+func (c *Cache) populate(key, stamp string, fill func(billy.Filesystem) error) (string, error) {
+	dir, err := os.MkdirTemp(c.root, key+versionSuffix)
+	if err != nil {
+		return "", err
+	}
+
+	complete := false
+
+	defer func() {
+		if !complete {
+			_ = os.RemoveAll(dir)
+		}
+	}()
+
+	err = fill(osfs.New(dir))
+	if err != nil {
+		return "", err
+	}
+
+	files, err := manifest(dir)
+	if err != nil {
+		return "", err
+	}
+
+	// Replacing the stamp switches to the new version at once, while readers
+	// of the previous one can go on using it.
+	err = writeFileAtomic(filepath.Join(c.root, key+stampSuffix),
+		[]byte(stamp+"\n"+filepath.Base(dir)+"\n"+files))
+	if err != nil {
+		return "", err
+	}
+
+	complete = true
+
+	return dir, nil
+}
+
+func writeFileAtomic(name string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+tmpSuffix)
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(data)
+	if err != nil {
+		f.Close()
+
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), name)
+}
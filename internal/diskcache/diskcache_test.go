@@ -0,0 +1,223 @@
+package diskcache_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git-fixtures/v6/internal/diskcache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	cache := diskcache.New(root)
+
+	var fills int
+
+	fill := func(content string) func(billy.Filesystem) error {
+		return func(fs billy.Filesystem) error {
+			fills++
+
+			return util.WriteFile(fs, "dir/file", []byte(content), 0o644)
+		}
+	}
+
+	first, err := cache.Get("entry", "v1", fill("first"))
+	require.NoError(t, err)
+	assert.Equal(t, root, filepath.Dir(first.Root()))
+
+	content, err := util.ReadFile(first, "dir/file")
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(content))
+
+	fs, err := cache.Get("entry", "v1", fill("ignored"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, fills)
+	assert.Equal(t, first.Root(), fs.Root())
+
+	second, err := cache.Get("entry", "v2", fill("second"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, fills)
+	assert.NotEqual(t, first.Root(), second.Root())
+
+	content, err = util.ReadFile(second, "dir/file")
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+
+	// Readers of the previous version are not disturbed.
+	content, err = util.ReadFile(first, "dir/file")
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(content))
+
+	entries, err := os.ReadDir(root)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+
+	assert.ElementsMatch(t, []string{
+		filepath.Base(first.Root()), filepath.Base(second.Root()), "entry.lock", "entry.stamp",
+	}, names)
+}
+
+func TestGetReadOnly(t *testing.T) {
+	t.Parallel()
+
+	fs, err := diskcache.New(t.TempDir()).Get("entry", "v1", func(fs billy.Filesystem) error {
+		return util.WriteFile(fs, "file", []byte("content"), 0o644)
+	})
+	require.NoError(t, err)
+
+	err = util.WriteFile(fs, "file", []byte("changed"), 0o644)
+	require.ErrorIs(t, err, os.ErrPermission)
+	require.ErrorIs(t, fs.Remove("file"), os.ErrPermission)
+
+	content, err := util.ReadFile(fs, "file")
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+}
+
+func TestGetMissingEntry(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	cache := diskcache.New(root)
+
+	var fills int
+
+	fill := func(fs billy.Filesystem) error {
+		fills++
+
+		return util.WriteFile(fs, "file", []byte("content"), 0o644)
+	}
+
+	fs, err := cache.Get("entry", "v1", fill)
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(fs.Root()))
+
+	fs, err = cache.Get("entry", "v1", fill)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fills)
+
+	_, err = fs.Stat("file")
+	require.NoError(t, err)
+}
+
+func TestGetFillError(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	cache := diskcache.New(root)
+
+	_, err := cache.Get("entry", "v1", func(billy.Filesystem) error {
+		return os.ErrInvalid
+	})
+	require.ErrorIs(t, err, os.ErrInvalid)
+
+	_, err = os.Stat(filepath.Join(root, "entry.stamp"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	entries, err := os.ReadDir(root)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "only the lock file is left")
+}
+
+func TestGetInvalidKey(t *testing.T) {
+	t.Parallel()
+
+	cache := diskcache.New(t.TempDir())
+
+	for _, key := range []string{"", ".", "..", "a/b", `a\b`} {
+		_, err := cache.Get(key, "v1", func(billy.Filesystem) error { return nil })
+		require.ErrorIs(t, err, diskcache.ErrInvalidKey, key)
+	}
+}
+
+func TestGetConcurrent(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	var (
+		fills atomic.Int32
+		wg    sync.WaitGroup
+	)
+
+	for range 8 {
+		wg.Go(func() {
+			fs, err := diskcache.New(root).Get("entry", "v1", func(fs billy.Filesystem) error {
+				fills.Add(1)
+
+				return util.WriteFile(fs, "file", []byte("content"), 0o644)
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			content, err := util.ReadFile(fs, "file")
+			assert.NoError(t, err)
+			assert.Equal(t, "content", string(content))
+		})
+	}
+
+	wg.Wait()
+	assert.Equal(t, int32(1), fills.Load())
+}
+
+func TestGetModifiedEntry(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]func(dir string) error{
+		"removed": func(dir string) error {
+			return os.Remove(filepath.Join(dir, "dir/file"))
+		},
+		"truncated": func(dir string) error {
+			return os.Truncate(filepath.Join(dir, "dir/file"), 1)
+		},
+		"added": func(dir string) error {
+			return os.WriteFile(filepath.Join(dir, "extra"), nil, 0o644)
+		},
+	}
+
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			cache := diskcache.New(root)
+
+			var fills int
+
+			fill := func(fs billy.Filesystem) error {
+				fills++
+
+				return util.WriteFile(fs, "dir/file", []byte("content"), 0o644)
+			}
+
+			fs, err := cache.Get("entry", "v1", fill)
+			require.NoError(t, err)
+			require.NoError(t, modify(fs.Root()))
+
+			fs, err = cache.Get("entry", "v1", fill)
+			require.NoError(t, err)
+			assert.Equal(t, 2, fills)
+
+			content, err := util.ReadFile(fs, "dir/file")
+			require.NoError(t, err)
+			assert.Equal(t, "content", string(content))
+
+			_, err = fs.Stat("extra")
+			require.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Package dotgit reads the parts of a .git directory required to derive
+// fixture metadata, without depending on go-git.
+package dotgit
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+)
+
+// Config holds the repository settings relevant to reading its contents.
+type Config struct {
+	// ObjectFormat is extensions.objectformat, defaulting to sha1.
+	ObjectFormat string
+	// RefStorage is extensions.refstorage, defaulting to "files".
+	RefStorage string
+}
+
+// ReadConfig parses the config file of the .git directory fs. A missing
+// config file yields the defaults.
+func ReadConfig(fs billy.Filesystem) (Config, error) {
+	cfg := Config{ObjectFormat: object.FormatSHA1, RefStorage: "files"}
+
+	data, err := util.ReadFile(fs, "config")
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+
+		return cfg, err
+	}
+
+	var section string
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			section = strings.ToLower(strings.Trim(line, "[]"))
+
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if section != "extensions" {
+			continue
+		}
+
+		switch key {
+		case "objectformat":
+			cfg.ObjectFormat = strings.ToLower(value)
+		case "refstorage":
+			cfg.RefStorage = strings.ToLower(value)
+		}
+	}
+
+	return cfg, s.Err()
+}
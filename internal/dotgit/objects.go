@@ -0,0 +1,205 @@
+package dotgit
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+	"github.com/go-git/go-git-fixtures/v6/internal/packfile"
+)
+
+var (
+	ErrObjectNotFound     = errors.New("object not found")
+	ErrMalformedLooseFile = errors.New("malformed loose object")
+)
+
+// ObjectStore gives access to the loose and packed objects of a .git
+// directory. Packs are decoded in full the first time an object is not found
+// as a loose object.
+type ObjectStore struct {
+	fs     billy.Filesystem
+	format string
+	packed map[string]packfile.ResolvedObject
+}
+
+// NewObjectStore returns an ObjectStore for the .git directory fs, whose
+// objects use the given format.
+func NewObjectStore(fs billy.Filesystem, format string) *ObjectStore {
+	return &ObjectStore{fs: fs, format: format, packed: nil}
+}
+
+// Get returns the type and content of the object hash.
+func (s *ObjectStore) Get(hash string) (object.Type, []byte, error) {
+	t, content, err := s.loose(hash)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return t, content, err
+	}
+
+	err = s.loadPacks()
+	if err != nil {
+		return object.InvalidType, nil, err
+	}
+
+	obj, ok := s.packed[hash]
+	if !ok {
+		return object.InvalidType, nil, fmt.Errorf("%w: %s", ErrObjectNotFound, hash)
+	}
+
+	return obj.ResolvedType, obj.Content, nil
+}
+
+// Hashes returns the hashes of every packed object, followed by every loose
+// object.
+func (s *ObjectStore) Hashes() ([]string, error) {
+	err := s.loadPacks()
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(s.packed))
+	for h := range s.packed {
+		hashes = append(hashes, h)
+	}
+
+	dirs, err := s.fs.ReadDir("objects")
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return hashes, nil
+		}
+
+		return nil, err
+	}
+
+	for _, dir := range dirs {
+		if !dir.IsDir() || len(dir.Name()) != 2 {
+			continue
+		}
+
+		files, err := s.fs.ReadDir(path.Join("objects", dir.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range files {
+			h := dir.Name() + f.Name()
+			if _, ok := s.packed[h]; !ok {
+				hashes = append(hashes, h)
+			}
+		}
+	}
+
+	return hashes, nil
+}
+
+// Peel follows annotated tags starting at hash and returns the first object
+// that is not a tag.
+func (s *ObjectStore) Peel(hash string) (string, error) {
+	for {
+		t, content, err := s.Get(hash)
+		if err != nil {
+			return "", err
+		}
+
+		if t != object.TagType {
+			return hash, nil
+		}
+
+		first, _, _ := strings.Cut(string(content), "\n")
+
+		target, ok := strings.CutPrefix(first, "object ")
+		if !ok {
+			return "", fmt.Errorf("%w: tag %s has no object header", ErrMalformedLooseFile, hash)
+		}
+
+		hash = target
+	}
+}
+
+func (s *ObjectStore) loose(hash string) (object.Type, []byte, error) {
+	if len(hash) < 3 {
+		return object.InvalidType, nil, fmt.Errorf("%w: %q", object.ErrInvalidHash, hash)
+	}
+
+	data, err := util.ReadFile(s.fs, path.Join("objects", hash[:2], hash[2:]))
+	if err != nil {
+		return object.InvalidType, nil, err
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return object.InvalidType, nil, fmt.Errorf("%w: %s: %w", ErrMalformedLooseFile, hash, err)
+	}
+
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return object.InvalidType, nil, fmt.Errorf("%w: %s: %w", ErrMalformedLooseFile, hash, err)
+	}
+
+	header, content, ok := bytes.Cut(raw, []byte{0})
+	if !ok {
+		return object.InvalidType, nil, fmt.Errorf("%w: %s: missing header", ErrMalformedLooseFile, hash)
+	}
+
+	typ, size, _ := strings.Cut(string(header), " ")
+
+	t, err := object.ParseType(typ)
+	if err != nil {
+		return object.InvalidType, nil, err
+	}
+
+	if n, err := strconv.Atoi(size); err != nil || n != len(content) {
+		return object.InvalidType, nil, fmt.Errorf("%w: %s: bad size %q", ErrMalformedLooseFile, hash, size)
+	}
+
+	return t, content, nil
+}
+
+func (s *ObjectStore) loadPacks() error {
+	if s.packed != nil {
+		return nil
+	}
+
+	s.packed = map[string]packfile.ResolvedObject{}
+
+	files, err := s.fs.ReadDir(path.Join("objects", "pack"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, f := range files {
+		// Only consider pack-<hash>.pack, stray files are ignored by git.
+		name, ok := strings.CutSuffix(f.Name(), ".pack")
+		if !ok || len(name) <= len("pack-") || !strings.HasPrefix(name, "pack-") {
+			continue
+		}
+
+		data, err := util.ReadFile(s.fs, path.Join("objects", "pack", f.Name()))
+		if err != nil {
+			return err
+		}
+
+		objects, err := packfile.Decode(s.format, data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name(), err)
+		}
+
+		for _, o := range objects {
+			s.packed[o.Hash] = o
+		}
+	}
+
+	return nil
+}
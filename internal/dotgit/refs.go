@@ -0,0 +1,217 @@
+package dotgit
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/util"
+)
+
+var (
+	ErrMalformedRef          = errors.New("malformed reference")
+	ErrUnsupportedRefStorage = errors.New("unsupported refstorage")
+)
+
+const (
+	symrefPrefix = "ref: "
+	tagsPrefix   = "refs/tags/"
+)
+
+// Ref is a single reference of a repository.
+type Ref struct {
+	// Name is the full reference name, e.g. refs/heads/master or HEAD.
+	Name string
+	// Target is the hash the reference points to. Empty for symbolic refs.
+	Target string
+	// Symbolic is the name of the reference a symbolic ref points to.
+	Symbolic string
+	// Peeled is the hash of the object an annotated tag ultimately points
+	// to, when known.
+	Peeled string
+	// Packed reports whether the reference is stored in packed-refs.
+	Packed bool
+}
+
+// ReadRefs returns every reference of the .git directory fs, sorted by name.
+// Depending on extensions.refstorage, refs are read from loose files and
+// packed-refs, or from the reftable stack. Loose refs take precedence over
+// packed ones.
+func ReadRefs(fs billy.Filesystem, cfg Config) ([]Ref, error) {
+	var (
+		refs []Ref
+		err  error
+	)
+
+	switch cfg.RefStorage {
+	case "files":
+		refs, err = readFilesRefs(fs)
+	case "reftable":
+		refs, err = readReftableStack(fs, cfg.ObjectFormat)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedRefStorage, cfg.RefStorage)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(refs, func(a, b Ref) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return refs, nil
+}
+
+func readFilesRefs(fs billy.Filesystem) ([]Ref, error) {
+	refs, err := readPackedRefs(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]int, len(refs))
+	for i, r := range refs {
+		byName[r.Name] = i
+	}
+
+	add := func(r Ref) {
+		if i, ok := byName[r.Name]; ok {
+			refs[i] = r
+
+			return
+		}
+
+		byName[r.Name] = len(refs)
+		refs = append(refs, r)
+	}
+
+	head, err := readLooseRef(fs, "HEAD")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if err == nil {
+		add(head)
+	}
+
+	err = util.Walk(fs, "refs", func(name string, fi os.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if fi.IsDir() {
+			return nil
+		}
+
+		r, err := readLooseRef(fs, name)
+		if err != nil {
+			return err
+		}
+
+		add(r)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+func readLooseRef(fs billy.Filesystem, name string) (Ref, error) {
+	data, err := util.ReadFile(fs, name)
+	if err != nil {
+		return Ref{}, err
+	}
+
+	r := Ref{Name: path.Clean(name), Target: "", Symbolic: "", Peeled: "", Packed: false}
+	line := strings.TrimSpace(string(data))
+
+	if target, ok := strings.CutPrefix(line, symrefPrefix); ok {
+		r.Symbolic = target
+
+		return r, nil
+	}
+
+	if line == "" {
+		return Ref{}, fmt.Errorf("%w: %s is empty", ErrMalformedRef, name)
+	}
+
+	r.Target = line
+
+	return r, nil
+}
+
+func readPackedRefs(fs billy.Filesystem) ([]Ref, error) {
+	data, err := util.ReadFile(fs, "packed-refs")
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var refs []Ref
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := s.Text()
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if peeled, ok := strings.CutPrefix(line, "^"); ok {
+			if len(refs) == 0 {
+				return nil, fmt.Errorf("%w: peeled line without ref", ErrMalformedRef)
+			}
+
+			refs[len(refs)-1].Peeled = peeled
+
+			continue
+		}
+
+		hash, name, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("%w: packed-refs line %q", ErrMalformedRef, line)
+		}
+
+		refs = append(refs, Ref{Name: name, Target: hash, Symbolic: "", Peeled: "", Packed: true})
+	}
+
+	return refs, s.Err()
+}
+
+// PeelRefs sets Peeled on every tag ref pointing to an annotated tag,
+// following the tag chain through the objects of store. As with git's
+// packed-refs, only refs under refs/tags/ are peeled, and refs already peeled
+// are left untouched.
+func PeelRefs(refs []Ref, store *ObjectStore) error {
+	for i, r := range refs {
+		if r.Target == "" || r.Peeled != "" || !strings.HasPrefix(r.Name, tagsPrefix) {
+			continue
+		}
+
+		peeled, err := store.Peel(r.Target)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+
+		if peeled != r.Target {
+			refs[i].Peeled = peeled
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package dotgit_test
+
+import (
+	"testing"
+
+	"github.com/go-git/go-billy/v6/memfs"
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git-fixtures/v6/internal/dotgit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRefsFiles(t *testing.T) {
+	t.Parallel()
+
+	fs := memfs.New()
+	files := map[string]string{
+		"HEAD":              "ref: refs/heads/master\n",
+		"refs/heads/master": "2222222222222222222222222222222222222222\n",
+		"packed-refs": "# pack-refs with: peeled fully-peeled sorted \n" +
+			"1111111111111111111111111111111111111111 refs/heads/master\n" +
+			"3333333333333333333333333333333333333333 refs/tags/v1\n" +
+			"^4444444444444444444444444444444444444444\n",
+	}
+
+	for name, content := range files {
+		require.NoError(t, util.WriteFile(fs, name, []byte(content), 0o644))
+	}
+
+	cfg, err := dotgit.ReadConfig(fs)
+	require.NoError(t, err)
+	assert.Equal(t, dotgit.Config{ObjectFormat: "sha1", RefStorage: "files"}, cfg)
+
+	refs, err := dotgit.ReadRefs(fs, cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, []dotgit.Ref{
+		{Name: "HEAD", Target: "", Symbolic: "refs/heads/master", Peeled: "", Packed: false},
+		{Name: "refs/heads/master", Target: "2222222222222222222222222222222222222222", Symbolic: "", Peeled: "", Packed: false},
+		{
+			Name: "refs/tags/v1", Target: "3333333333333333333333333333333333333333", Symbolic: "",
+			Peeled: "4444444444444444444444444444444444444444", Packed: true,
+		},
+	}, refs)
+}
+
+func TestReadRefsUnsupportedStorage(t *testing.T) {
+	t.Parallel()
+
+	_, err := dotgit.ReadRefs(memfs.New(), dotgit.Config{ObjectFormat: "sha1", RefStorage: "unknown"})
+	require.ErrorIs(t, err, dotgit.ErrUnsupportedRefStorage)
+}
@@ -0,0 +1,266 @@
+package dotgit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+)
+
+var ErrMalformedReftable = errors.New("malformed reftable")
+
+const (
+	reftableMagic      = "REFT"
+	reftableVersion2   = 2
+	reftableHeaderV1   = 24
+	reftableHeaderV2   = 28
+	reftableFooterV1   = 68
+	reftableFooterV2   = 72
+	reftableRefBlock   = 'r'
+	reftableBlockHead  = 4
+	reftableRestartLen = 3
+	reftableCountLen   = 2
+
+	valueDeletion = 0
+	valueHash     = 1
+	valuePeeled   = 2
+	valueSymref   = 3
+	valueTypeMask = 0x7
+	valueTypeBits = 3
+
+	varintContinue  = 0x80
+	varintValueMask = 0x7f
+	varintShift     = 7
+)
+
+// readReftableStack merges the tables listed in reftable/tables.list, later
+// tables overriding earlier ones.
+func readReftableStack(fs billy.Filesystem, format string) ([]Ref, error) {
+	hashSize, err := object.HashSize(format)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := util.ReadFile(fs, path.Join("reftable", "tables.list"))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		refs  []Ref
+		index = map[string]int{}
+	)
+
+	s := bufio.NewScanner(bytes.NewReader(list))
+	for s.Scan() {
+		name := s.Text()
+		if name == "" {
+			continue
+		}
+
+		data, err := util.ReadFile(fs, path.Join("reftable", name))
+		if err != nil {
+			return nil, err
+		}
+
+		err = readReftable(data, hashSize, func(r Ref, deleted bool) {
+			i, ok := index[r.Name]
+
+			switch {
+			case deleted && ok:
+				refs[i].Name = ""
+				delete(index, r.Name)
+			case deleted:
+			case ok:
+				refs[i] = r
+			default:
+				index[r.Name] = len(refs)
+				refs = append(refs, r)
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	live := refs[:0]
+	for _, r := range refs {
+		if r.Name != "" {
+			live = append(live, r)
+		}
+	}
+
+	return live, nil
+}
+
+// readReftable calls fn for every record of the ref blocks of a single table.
+// Other block types (obj, index, log) are not needed and are skipped.
+func readReftable(data []byte, hashSize int, fn func(r Ref, deleted bool)) error {
+	if len(data) < reftableHeaderV1 || string(data[:4]) != reftableMagic {
+		return fmt.Errorf("%w: bad magic", ErrMalformedReftable)
+	}
+
+	headerLen, footerLen := reftableHeaderV1, reftableFooterV1
+	if data[4] == reftableVersion2 {
+		headerLen, footerLen = reftableHeaderV2, reftableFooterV2
+	}
+
+	if len(data) < headerLen+footerLen {
+		return fmt.Errorf("%w: truncated", ErrMalformedReftable)
+	}
+
+	blockSize := int(readUint24(data[5:]))
+	end := len(data) - footerLen
+
+	for offset := 0; offset < end; {
+		start := offset
+		if offset == 0 {
+			start = headerLen
+		}
+
+		if start+reftableBlockHead > end || data[start] != reftableRefBlock {
+			return nil
+		}
+
+		blockEnd := offset + int(readUint24(data[start+1:]))
+		if blockEnd > end || blockEnd < start+reftableBlockHead+reftableCountLen {
+			return fmt.Errorf("%w: block at %d overflows", ErrMalformedReftable, offset)
+		}
+
+		err := readRefBlock(data[start+reftableBlockHead:blockEnd], hashSize, fn)
+		if err != nil {
+			return fmt.Errorf("block at %d: %w", offset, err)
+		}
+
+		if blockSize == 0 {
+			offset = blockEnd
+		} else {
+			offset += blockSize
+		}
+	}
+
+	return nil
+}
+
+func readRefBlock(block []byte, hashSize int, fn func(r Ref, deleted bool)) error {
+	restarts := int(binary.BigEndian.Uint16(block[len(block)-reftableCountLen:]))
+
+	recordsEnd := len(block) - reftableCountLen - restarts*reftableRestartLen
+	if recordsEnd < 0 {
+		return fmt.Errorf("%w: restart table overflows", ErrMalformedReftable)
+	}
+
+	var (
+		buf  = block[:recordsEnd]
+		last []byte
+	)
+
+	for len(buf) > 0 {
+		prefix, n := readVarint(buf)
+		if n == 0 || prefix > uint64(len(last)) {
+			return fmt.Errorf("%w: bad key prefix", ErrMalformedReftable)
+		}
+
+		buf = buf[n:]
+
+		suffixType, n := readVarint(buf)
+		if n == 0 {
+			return fmt.Errorf("%w: bad key suffix", ErrMalformedReftable)
+		}
+
+		buf = buf[n:]
+		suffixLen := int(suffixType >> valueTypeBits) //nolint:gosec
+		valueType := suffixType & valueTypeMask
+
+		if suffixLen > len(buf) {
+			return fmt.Errorf("%w: key overflows", ErrMalformedReftable)
+		}
+
+		key := append(bytes.Clone(last[:prefix]), buf[:suffixLen]...)
+		buf = buf[suffixLen:]
+		last = key
+
+		// update_index_delta
+		_, n = readVarint(buf)
+		if n == 0 {
+			return fmt.Errorf("%w: bad update index", ErrMalformedReftable)
+		}
+
+		buf = buf[n:]
+
+		r := Ref{Name: string(key), Target: "", Symbolic: "", Peeled: "", Packed: false}
+
+		switch valueType {
+		case valueDeletion:
+			fn(r, true)
+
+			continue
+		case valueHash, valuePeeled:
+			hashes := 1
+			if valueType == valuePeeled {
+				hashes = 2
+			}
+
+			if len(buf) < hashes*hashSize {
+				return fmt.Errorf("%w: value overflows", ErrMalformedReftable)
+			}
+
+			r.Target = hex.EncodeToString(buf[:hashSize])
+			if valueType == valuePeeled {
+				r.Peeled = hex.EncodeToString(buf[hashSize : 2*hashSize])
+			}
+
+			buf = buf[hashes*hashSize:]
+		case valueSymref:
+			size, n := readVarint(buf)
+			if n == 0 || size > uint64(len(buf)-n) {
+				return fmt.Errorf("%w: symref overflows", ErrMalformedReftable)
+			}
+
+			r.Symbolic = string(buf[n : n+int(size)]) //nolint:gosec
+			buf = buf[n+int(size):]                   //nolint:gosec
+		default:
+			return fmt.Errorf("%w: unknown value type %d", ErrMalformedReftable, valueType)
+		}
+
+		fn(r, false)
+	}
+
+	return nil
+}
+
+// readVarint decodes a varint as used by reftable (and packfile OFS deltas),
+// returning the value and the number of bytes read, or zero bytes read if
+// buf is truncated.
+func readVarint(buf []byte) (uint64, int) {
+	var v uint64
+
+	for i, b := range buf {
+		if i > 0 {
+			v++
+		}
+
+		v = v<<varintShift | uint64(b&varintValueMask)
+
+		if b&varintContinue == 0 {
+			return v, i + 1
+		}
+	}
+
+	return 0, 0
+}
+
+func readUint24(b []byte) uint32 {
+	return uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
+}
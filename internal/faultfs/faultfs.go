@@ -0,0 +1,413 @@
+// Package faultfs implements a billy.Filesystem wrapper failing the
+// operations chosen by an Injector, to test how callers cope with I/O
+// errors.
+package faultfs
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-billy/v6"
+)
+
+// Op is a kind of filesystem operation, used as a bit mask.
+type Op uint32
+
+const (
+	// Open is for Create, Open, OpenFile and TempFile.
+	Open Op = 1 << iota
+	// Read is for Read and ReadAt on files.
+	Read
+	// Write is for Write, WriteAt and Truncate on files.
+	Write
+	// Close is for Close on files. Files are closed even when it fails.
+	Close
+	// Sync is for Sync on files.
+	Sync
+	// Stat is for Stat and Lstat, on filesystems and files.
+	Stat
+	// Rename is for Rename, which is given both paths.
+	Rename
+	// Remove is for Remove.
+	Remove
+	// Mkdir is for MkdirAll.
+	Mkdir
+	// ReadDir is for ReadDir.
+	ReadDir
+	// Symlink is for Symlink and Readlink.
+	Symlink
+	// Chmod is for Chmod, Chown, Lchown and Chtimes.
+	Chmod
+)
+
+// Fault is the outcome of an operation chosen by an Injector. The zero value
+// lets the operation through.
+type Fault struct {
+	// Err is returned by the operation, which is not carried out.
+	Err error
+	// Short makes reads return fewer bytes than requested, and writes write
+	// fewer bytes and fail with io.ErrShortWrite. It is ignored if Err is set.
+	Short bool
+}
+
+// Injector chooses the fault of the operation op on names, slash-separated
+// paths relative to the root of the filesystem. Every operation is given a
+// single name, except Rename which is given the old and new paths in one
+// call.
+type Injector func(op Op, names ...string) Fault
+
+type faultFS struct {
+	fs     billy.Filesystem
+	inject Injector
+}
+
+// New returns a filesystem forwarding to fs, where operations fail as
+// chosen by inject. Filesystems returned by Chroot use the same injector,
+// with paths relative to their own root.
+func New(fs billy.Filesystem, inject Injector) billy.Filesystem {
+	return &faultFS{fs: fs, inject: inject}
+}
+
+// NewFile returns a file forwarding to f, where operations fail as chosen
+// by inject, which is given name as the path of the file.
+func NewFile(f billy.File, name string, inject Injector) billy.File {
+	return &file{File: f, name: clean(name), inject: inject}
+}
+
+func (f *faultFS) fail(op Op, opName, name string) error {
+	fault := f.inject(op, clean(name))
+	if fault.Err == nil {
+		return nil
+	}
+
+	return &fs.PathError{Op: opName, Path: name, Err: fault.Err}
+}
+
+func (f *faultFS) Create(filename string) (billy.File, error) {
+	return f.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o666)
+}
+
+func (f *faultFS) Open(filename string) (billy.File, error) {
+	return f.OpenFile(filename, os.O_RDONLY, 0)
+}
+
+func (f *faultFS) OpenFile(filename string, flag int, perm fs.FileMode) (billy.File, error) {
+	err := f.fail(Open, "open", filename)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := f.fs.OpenFile(filename, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFile(file, filename, f.inject), nil
+}
+
+func (f *faultFS) Stat(filename string) (fs.FileInfo, error) {
+	err := f.fail(Stat, "stat", filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.fs.Stat(filename)
+}
+
+func (f *faultFS) Lstat(filename string) (fs.FileInfo, error) {
+	err := f.fail(Stat, "lstat", filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.fs.Lstat(filename)
+}
+
+func (f *faultFS) Rename(oldpath, newpath string) error {
+	fault := f.inject(Rename, clean(oldpath), clean(newpath))
+	if fault.Err != nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: fault.Err}
+	}
+
+	return f.fs.Rename(oldpath, newpath)
+}
+
+func (f *faultFS) Remove(filename string) error {
+	err := f.fail(Remove, "remove", filename)
+	if err != nil {
+		return err
+	}
+
+	return f.fs.Remove(filename)
+}
+
+func (f *faultFS) Join(elem ...string) string {
+	return f.fs.Join(elem...)
+}
+
+func (f *faultFS) TempFile(dir, prefix string) (billy.File, error) {
+	err := f.fail(Open, "open", path.Join(dir, prefix))
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := f.fs.TempFile(dir, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFile(file, file.Name(), f.inject), nil
+}
+
+func (f *faultFS) ReadDir(dirname string) ([]fs.DirEntry, error) {
+	err := f.fail(ReadDir, "readdir", dirname)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.fs.ReadDir(dirname)
+}
+
+func (f *faultFS) MkdirAll(filename string, perm fs.FileMode) error {
+	err := f.fail(Mkdir, "mkdir", filename)
+	if err != nil {
+		return err
+	}
+
+	return f.fs.MkdirAll(filename, perm)
+}
+
+func (f *faultFS) Symlink(target, link string) error {
+	err := f.fail(Symlink, "symlink", link)
+	if err != nil {
+		return err
+	}
+
+	return f.fs.Symlink(target, link)
+}
+
+func (f *faultFS) Readlink(link string) (string, error) {
+	err := f.fail(Symlink, "readlink", link)
+	if err != nil {
+		return "", err
+	}
+
+	return f.fs.Readlink(link)
+}
+
+func (f *faultFS) Chroot(p string) (billy.Filesystem, error) {
+	fs, err := f.fs.Chroot(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(fs, f.inject), nil
+}
+
+func (f *faultFS) Root() string {
+	return f.fs.Root()
+}
+
+func (f *faultFS) Capabilities() billy.Capability {
+	return billy.Capabilities(f.fs)
+}
+
+func (f *faultFS) Chmod(name string, mode fs.FileMode) error {
+	c, ok := f.fs.(billy.Chmod)
+	if !ok {
+		return billy.ErrNotSupported
+	}
+
+	err := f.fail(Chmod, "chmod", name)
+	if err != nil {
+		return err
+	}
+
+	return c.Chmod(name, mode)
+}
+
+func (f *faultFS) Lchown(name string, uid, gid int) error {
+	c, ok := f.fs.(billy.Change)
+	if !ok {
+		return billy.ErrNotSupported
+	}
+
+	err := f.fail(Chmod, "lchown", name)
+	if err != nil {
+		return err
+	}
+
+	return c.Lchown(name, uid, gid)
+}
+
+func (f *faultFS) Chown(name string, uid, gid int) error {
+	c, ok := f.fs.(billy.Change)
+	if !ok {
+		return billy.ErrNotSupported
+	}
+
+	err := f.fail(Chmod, "chown", name)
+	if err != nil {
+		return err
+	}
+
+	return c.Chown(name, uid, gid)
+}
+
+func (f *faultFS) Chtimes(name string, atime, mtime time.Time) error {
+	c, ok := f.fs.(billy.Change)
+	if !ok {
+		return billy.ErrNotSupported
+	}
+
+	err := f.fail(Chmod, "chtimes", name)
+	if err != nil {
+		return err
+	}
+
+	return c.Chtimes(name, atime, mtime)
+}
+
+type file struct {
+	billy.File
+
+	name   string
+	inject Injector
+}
+
+func (f *file) fault(op Op, opName string) (Fault, error) {
+	fault := f.inject(op, f.name)
+	if fault.Err != nil {
+		return fault, &fs.PathError{Op: opName, Path: f.Name(), Err: fault.Err}
+	}
+
+	return fault, nil
+}
+
+func (f *file) Read(p []byte) (int, error) {
+	fault, err := f.fault(Read, "read")
+	if err != nil {
+		return 0, err
+	}
+
+	return f.File.Read(shorten(p, fault))
+}
+
+func (f *file) ReadAt(p []byte, off int64) (int, error) {
+	fault, err := f.fault(Read, "read")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := f.File.ReadAt(shorten(p, fault), off)
+	if err == nil && n < len(p) {
+		// ReadAt must report why it returned fewer bytes than requested.
+		err = io.ErrUnexpectedEOF
+	}
+
+	return n, err
+}
+
+func (f *file) Write(p []byte) (int, error) {
+	fault, err := f.fault(Write, "write")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := f.File.Write(shorten(p, fault))
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+
+	return n, err
+}
+
+func (f *file) WriteAt(p []byte, off int64) (int, error) {
+	fault, err := f.fault(Write, "write")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := f.File.WriteAt(shorten(p, fault), off)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+
+	return n, err
+}
+
+func (f *file) Truncate(size int64) error {
+	_, err := f.fault(Write, "truncate")
+	if err != nil {
+		return err
+	}
+
+	return f.File.Truncate(size)
+}
+
+func (f *file) Close() error {
+	_, ferr := f.fault(Close, "close")
+
+	err := f.File.Close()
+	if ferr != nil {
+		return ferr
+	}
+
+	return err
+}
+
+func (f *file) Stat() (fs.FileInfo, error) {
+	_, err := f.fault(Stat, "stat")
+	if err != nil {
+		return nil, err
+	}
+
+	return f.File.Stat()
+}
+
+func (f *file) Sync() error {
+	_, err := f.fault(Sync, "sync")
+	if err != nil {
+		return err
+	}
+
+	if s, ok := f.File.(billy.Syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+func (f *file) Lock() error {
+	if l, ok := f.File.(billy.Locker); ok {
+		return l.Lock()
+	}
+
+	return nil
+}
+
+func (f *file) Unlock() error {
+	if l, ok := f.File.(billy.Locker); ok {
+		return l.Unlock()
+	}
+
+	return nil
+}
+
+// shorten returns the first half of p if fault is a short one.
+func shorten(p []byte, fault Fault) []byte {
+	if !fault.Short || len(p) < 2 {
+		return p
+	}
+
+	return p[:len(p)/2]
+}
+
+// clean returns name as a slash-separated path relative to the root.
+func clean(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, "\\", "/")), "/")
+}
@@ -0,0 +1,180 @@
+package faultfs_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"syscall"
+	"testing"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/memfs"
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git-fixtures/v6/internal/faultfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type call struct {
+	op   faultfs.Op
+	name string
+}
+
+// newFS returns a filesystem holding dir/file, whose operations on name are
+// failed with fault, along with the operations it was asked about.
+func newFS(t *testing.T, name string, fault faultfs.Fault) (billy.Filesystem, *[]call) {
+	t.Helper()
+
+	base := memfs.New()
+	require.NoError(t, util.WriteFile(base, "dir/file", []byte("0123456789"), 0o644))
+
+	calls := &[]call{}
+	fs := faultfs.New(base, func(op faultfs.Op, names ...string) faultfs.Fault {
+		for _, n := range names {
+			*calls = append(*calls, call{op, n})
+		}
+
+		if !slices.Contains(names, name) {
+			return faultfs.Fault{}
+		}
+
+		return fault
+	})
+
+	return fs, calls
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	fs, calls := newFS(t, "dir/file", faultfs.Fault{Err: syscall.EIO})
+
+	_, err := fs.Open("dir/file")
+	require.ErrorIs(t, err, syscall.EIO)
+
+	var perr *os.PathError
+	require.ErrorAs(t, err, &perr)
+	assert.Equal(t, "open", perr.Op)
+
+	_, err = fs.Stat("/dir/../dir/file")
+	require.ErrorIs(t, err, syscall.EIO)
+
+	require.ErrorIs(t, fs.Remove("dir/file"), syscall.EIO)
+	require.ErrorIs(t, fs.Rename("dir/file", "other"), syscall.EIO)
+	require.ErrorIs(t, fs.Rename("other", "dir/file"), syscall.EIO)
+
+	_, err = fs.Stat("dir")
+	require.NoError(t, err)
+
+	assert.Equal(t, call{faultfs.Open, "dir/file"}, (*calls)[0])
+	assert.Equal(t, call{faultfs.Stat, "dir/file"}, (*calls)[1])
+}
+
+func TestFileErrors(t *testing.T) {
+	t.Parallel()
+
+	var fail faultfs.Op
+
+	base := memfs.New()
+	require.NoError(t, util.WriteFile(base, "file", []byte("content"), 0o644))
+
+	fs := faultfs.New(base, func(op faultfs.Op, _ ...string) faultfs.Fault {
+		if op == fail {
+			return faultfs.Fault{Err: syscall.ENOSPC}
+		}
+
+		return faultfs.Fault{}
+	})
+
+	f, err := fs.OpenFile("file", os.O_RDWR, 0)
+	require.NoError(t, err)
+
+	for _, op := range []faultfs.Op{faultfs.Read, faultfs.Write, faultfs.Stat, faultfs.Sync} {
+		fail = op
+
+		_, rerr := f.Read(make([]byte, 1))
+		_, werr := f.Write([]byte("x"))
+		_, serr := f.Stat()
+		yerr := f.(billy.Syncer).Sync()
+
+		for o, err := range map[faultfs.Op]error{
+			faultfs.Read: rerr, faultfs.Write: werr, faultfs.Stat: serr, faultfs.Sync: yerr,
+		} {
+			if o == op {
+				require.ErrorIs(t, err, syscall.ENOSPC, "op %d", o)
+			} else {
+				require.NoError(t, err, "op %d", o)
+			}
+		}
+	}
+
+	fail = faultfs.Close
+	require.ErrorIs(t, f.Close(), syscall.ENOSPC)
+
+	fail = 0
+	require.ErrorIs(t, f.Close(), os.ErrClosed, "file not closed on failure")
+}
+
+func TestShort(t *testing.T) {
+	t.Parallel()
+
+	fs, _ := newFS(t, "dir/file", faultfs.Fault{Short: true})
+
+	f, err := fs.OpenFile("dir/file", os.O_RDWR, 0)
+	require.NoError(t, err)
+
+	buf := make([]byte, 8)
+	n, err := f.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "0123", string(buf[:n]))
+
+	n, err = f.ReadAt(buf, 0)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, 4, n)
+
+	n, err = f.Write([]byte("abcd"))
+	require.ErrorIs(t, err, io.ErrShortWrite)
+	assert.Equal(t, 2, n)
+	require.NoError(t, f.Close())
+}
+
+func TestChroot(t *testing.T) {
+	t.Parallel()
+
+	fs, calls := newFS(t, "file", faultfs.Fault{Err: syscall.EIO})
+
+	sub, err := fs.Chroot("dir")
+	require.NoError(t, err)
+
+	_, err = sub.Open("file")
+	require.ErrorIs(t, err, syscall.EIO)
+
+	_, err = fs.Open("dir/file")
+	require.NoError(t, err)
+
+	assert.Equal(t, []call{{faultfs.Open, "file"}, {faultfs.Open, "dir/file"}}, *calls)
+}
+
+func TestPassThrough(t *testing.T) {
+	t.Parallel()
+
+	fs, _ := newFS(t, "", faultfs.Fault{Err: errors.New("unexpected")})
+
+	require.NoError(t, util.WriteFile(fs, "new/file", []byte("new"), 0o644))
+	require.NoError(t, fs.Rename("new/file", "new/renamed"))
+
+	content, err := util.ReadFile(fs, "new/renamed")
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(content))
+
+	require.NoError(t, fs.Symlink("new/renamed", "link"))
+
+	target, err := fs.Readlink("link")
+	require.NoError(t, err)
+	assert.Equal(t, "new/renamed", target)
+
+	entries, err := fs.ReadDir("new")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
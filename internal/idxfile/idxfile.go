@@ -0,0 +1,198 @@
+// Package idxfile encodes and decodes version 2 packfile index files.
+package idxfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+)
+
+var (
+	ErrInvalidOffset    = errors.New("invalid offset")
+	ErrInvalidThreshold = errors.New("invalid large offset threshold")
+	ErrMalformedIdx     = errors.New("malformed idx file")
+)
+
+const (
+	// Magic is the signature at the start of version 2 idx files.
+	Magic = "\xfftOc"
+	// VersionSupported is the only idx version written and read.
+	VersionSupported uint32 = 2
+	// LargeOffsetThreshold is the lowest offset Encode writes to the large
+	// offset table, the first one not fitting in 31 bits.
+	LargeOffsetThreshold int64 = maxSmallOffset + 1
+
+	fanoutEntries  = 256
+	isLargeOffset  = 0x80000000
+	maxSmallOffset = 0x7fffffff
+)
+
+// Entry is an object recorded in an idx file.
+type Entry struct {
+	Hash   string
+	Offset int64
+	CRC32  uint32
+}
+
+// Encode returns a version 2 idx file for entries of the pack whose checksum
+// is packChecksum. Entries may be given in any order.
+func Encode(format string, entries []Entry, packChecksum []byte) ([]byte, error) {
+	return EncodeWithThreshold(format, entries, packChecksum, LargeOffsetThreshold)
+}
+
+// EncodeWithThreshold is like Encode, but writes offsets of threshold and
+// above to the large offset table. The threshold must be between 1 and
+// LargeOffsetThreshold.
+func EncodeWithThreshold(format string, entries []Entry, packChecksum []byte, threshold int64) ([]byte, error) {
+	if threshold < 1 || threshold > LargeOffsetThreshold {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidThreshold, threshold)
+	}
+
+	sorted := slices.Clone(entries)
+	slices.SortFunc(sorted, func(a, b Entry) int {
+		return strings.Compare(a.Hash, b.Hash)
+	})
+
+	hashes := make([][]byte, len(sorted))
+
+	var fanout [fanoutEntries]uint32
+
+	for i, e := range sorted {
+		h, err := object.DecodeHash(format, e.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		hashes[i] = h
+		for j := int(h[0]); j < fanoutEntries; j++ {
+			fanout[j]++
+		}
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(Magic)
+	write(&buf, VersionSupported)
+	write(&buf, fanout)
+
+	for _, h := range hashes {
+		buf.Write(h)
+	}
+
+	for _, e := range sorted {
+		write(&buf, e.CRC32)
+	}
+
+	var large []uint64
+
+	for _, e := range sorted {
+		if e.Offset < 0 {
+			return nil, fmt.Errorf("%w: %d for %s", ErrInvalidOffset, e.Offset, e.Hash)
+		}
+
+		if e.Offset >= threshold {
+			write(&buf, uint32(isLargeOffset|len(large)))
+			large = append(large, uint64(e.Offset))
+
+			continue
+		}
+
+		write(&buf, uint32(e.Offset))
+	}
+
+	for _, o := range large {
+		write(&buf, o)
+	}
+
+	buf.Write(packChecksum)
+
+	h, err := object.NewHasher(format)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Write(buf.Bytes())
+	buf.Write(h.Sum(nil))
+
+	return buf.Bytes(), nil
+}
+
+func write(buf *bytes.Buffer, v any) {
+	// Writes into a bytes.Buffer cannot fail.
+	_ = binary.Write(buf, binary.BigEndian, v)
+}
+
+// Index is a decoded idx file.
+type Index struct {
+	// Entries are ordered by hash.
+	Entries      []Entry
+	PackChecksum []byte
+	Checksum     []byte
+}
+
+// Decode parses a version 2 idx file.
+func Decode(format string, data []byte) (*Index, error) {
+	hashSize, err := object.HashSize(format)
+	if err != nil {
+		return nil, err
+	}
+
+	headerSize := len(Magic) + 4 + fanoutEntries*4
+	if len(data) < headerSize+2*hashSize {
+		return nil, fmt.Errorf("%w: file too short", ErrMalformedIdx)
+	}
+
+	if string(data[:len(Magic)]) != Magic {
+		return nil, fmt.Errorf("%w: bad signature", ErrMalformedIdx)
+	}
+
+	version := binary.BigEndian.Uint32(data[len(Magic):])
+	if version != VersionSupported {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrMalformedIdx, version)
+	}
+
+	count := int(binary.BigEndian.Uint32(data[headerSize-4:]))
+
+	namesStart := headerSize
+	crcStart := namesStart + count*hashSize
+	offsetsStart := crcStart + count*4
+	largeStart := offsetsStart + count*4
+
+	if len(data) < largeStart+2*hashSize {
+		return nil, fmt.Errorf("%w: file too short for %d objects", ErrMalformedIdx, count)
+	}
+
+	largeCount := (len(data) - largeStart - 2*hashSize) / 8
+
+	idx := &Index{
+		Entries:      make([]Entry, 0, count),
+		PackChecksum: data[len(data)-2*hashSize : len(data)-hashSize],
+		Checksum:     data[len(data)-hashSize:],
+	}
+
+	for i := range count {
+		offset := int64(binary.BigEndian.Uint32(data[offsetsStart+i*4:]))
+		if offset&isLargeOffset != 0 {
+			j := int(offset &^ isLargeOffset)
+			if j >= largeCount {
+				return nil, fmt.Errorf("%w: large offset %d out of range", ErrMalformedIdx, j)
+			}
+
+			offset = int64(binary.BigEndian.Uint64(data[largeStart+j*8:])) //nolint:gosec // offsets fit in int64.
+		}
+
+		idx.Entries = append(idx.Entries, Entry{
+			Hash:   hex.EncodeToString(data[namesStart+i*hashSize : namesStart+(i+1)*hashSize]),
+			Offset: offset,
+			CRC32:  binary.BigEndian.Uint32(data[crcStart+i*4:]),
+		})
+	}
+
+	return idx, nil
+}
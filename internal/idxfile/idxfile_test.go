@@ -0,0 +1,81 @@
+package idxfile_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/idxfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeLargeOffsets(t *testing.T) {
+	t.Parallel()
+
+	entries := []idxfile.Entry{
+		{Hash: "3000000000000000000000000000000000000000", Offset: 1 << 40, CRC32: 3},
+		{Hash: "1000000000000000000000000000000000000000", Offset: 12, CRC32: 1},
+		{Hash: "2000000000000000000000000000000000000000", Offset: 1 << 31, CRC32: 2},
+		{Hash: "4000000000000000000000000000000000000000", Offset: 1<<31 - 1, CRC32: 4},
+	}
+
+	data, err := idxfile.Encode("sha1", entries, bytes.Repeat([]byte{0xaa}, 20))
+	require.NoError(t, err)
+
+	idx, err := idxfile.Decode("sha1", data)
+	require.NoError(t, err)
+
+	assert.Equal(t, []idxfile.Entry{entries[1], entries[2], entries[0], entries[3]}, idx.Entries)
+
+	// Two offsets do not fit in 31 bits and go to the large offset table,
+	// between the offsets and the trailing checksums.
+	assert.Len(t, data, 8+256*4+4*(20+4+4)+2*8+2*20)
+}
+
+func TestEncodeWithThreshold(t *testing.T) {
+	t.Parallel()
+
+	entries := []idxfile.Entry{
+		{Hash: "1000000000000000000000000000000000000000", Offset: 12, CRC32: 1},
+		{Hash: "2000000000000000000000000000000000000000", Offset: 100, CRC32: 2},
+		{Hash: "3000000000000000000000000000000000000000", Offset: 200, CRC32: 3},
+	}
+
+	data, err := idxfile.EncodeWithThreshold("sha1", entries, bytes.Repeat([]byte{0xaa}, 20), 100)
+	require.NoError(t, err)
+
+	idx, err := idxfile.Decode("sha1", data)
+	require.NoError(t, err)
+
+	assert.Equal(t, entries, idx.Entries)
+	assert.Len(t, data, 8+256*4+3*(20+4+4)+2*8+2*20)
+
+	for _, threshold := range []int64{0, idxfile.LargeOffsetThreshold + 1} {
+		_, err = idxfile.EncodeWithThreshold("sha1", entries, make([]byte, 20), threshold)
+		require.ErrorIs(t, err, idxfile.ErrInvalidThreshold)
+	}
+}
+
+func TestDecodeLargeOffsetOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	entries := []idxfile.Entry{{Hash: "1000000000000000000000000000000000000000", Offset: 1 << 32, CRC32: 0}}
+
+	data, err := idxfile.Encode("sha1", entries, make([]byte, 20))
+	require.NoError(t, err)
+
+	// Point the entry at the second large offset, which does not exist.
+	data[8+256*4+20+4+3] = 1
+
+	_, err = idxfile.Decode("sha1", data)
+	require.ErrorIs(t, err, idxfile.ErrMalformedIdx)
+}
+
+func TestEncodeNegativeOffset(t *testing.T) {
+	t.Parallel()
+
+	entries := []idxfile.Entry{{Hash: "1000000000000000000000000000000000000000", Offset: -1, CRC32: 0}}
+
+	_, err := idxfile.Encode("sha1", entries, make([]byte, 20))
+	require.ErrorIs(t, err, idxfile.ErrInvalidOffset)
+}
@@ -0,0 +1,405 @@
+// Package index decodes git index (staging area) files, versions 2 to 4.
+package index
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+)
+
+var (
+	ErrMalformedIndex     = errors.New("malformed index")
+	ErrUnsupportedVersion = errors.New("unsupported index version")
+)
+
+const (
+	Signature  = "DIRC"
+	MinVersion = 2
+	MaxVersion = 4
+
+	// extendedVersion is the first version allowing extended entry flags.
+	extendedVersion = 3
+
+	headerSize     = 12
+	extHeaderSize  = 8
+	statSize       = 40
+	flagsSize      = 2
+	extFlagsSize   = 2
+	entryAlignment = 8
+	eoieOffsetSize = 4
+
+	flagAssumeValid  = 0x8000
+	flagExtended     = 0x4000
+	flagStageMask    = 0x3000
+	flagStageShift   = 12
+	flagSkipWorktree = 0x4000
+	flagIntentToAdd  = 0x2000
+
+	modeOffset = 24
+
+	varintContinue  = 0x80
+	varintValueMask = 0x7f
+	varintShift     = 7
+
+	stages = 3
+)
+
+// Extension signatures.
+const (
+	ExtTree              = "TREE"
+	ExtResolveUndo       = "REUC"
+	ExtEndOfIndexEntries = "EOIE"
+)
+
+// Entry is a single entry of the index.
+type Entry struct {
+	Path         string
+	Mode         uint32
+	Stage        int
+	Hash         string
+	AssumeValid  bool
+	SkipWorktree bool
+	IntentToAdd  bool
+}
+
+// Tree is a node of the cached tree (TREE) extension.
+type Tree struct {
+	// Path is the full path of the directory, empty for the root.
+	Path string
+	// Entries is the number of index entries covered, -1 if invalidated.
+	Entries int
+	// Subtrees is the number of direct subtrees.
+	Subtrees int
+	// Hash is the tree hash, empty if invalidated.
+	Hash string
+}
+
+// ResolveUndo is an entry of the resolve undo (REUC) extension, holding the
+// conflicting stages 1 to 3 of a resolved path. Missing stages have a zero
+// mode and empty hash.
+type ResolveUndo struct {
+	Path   string
+	Modes  [stages]uint32
+	Hashes [stages]string
+}
+
+// EndOfIndexEntries is the end of index entries (EOIE) extension.
+type EndOfIndexEntries struct {
+	// Offset is the offset of the first extension in the index file.
+	Offset uint32
+	// Hash is the hex-encoded hash of the extension headers.
+	Hash string
+}
+
+// Index is a decoded index file.
+type Index struct {
+	Version           int
+	Entries           []Entry
+	Tree              []Tree
+	ResolveUndo       []ResolveUndo
+	EndOfIndexEntries *EndOfIndexEntries
+	// Extensions are the signatures of every extension, in file order.
+	Extensions []string
+}
+
+// Decode parses the index file data, whose hashes use the given object
+// format.
+func Decode(format string, data []byte) (*Index, error) {
+	hashSize, err := object.HashSize(format)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < headerSize+hashSize || string(data[:4]) != Signature {
+		return nil, fmt.Errorf("%w: bad signature", ErrMalformedIndex)
+	}
+
+	version := int(binary.BigEndian.Uint32(data[4:]))
+	if version < MinVersion || version > MaxVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+
+	idx := &Index{
+		Version:           version,
+		Entries:           nil,
+		Tree:              nil,
+		ResolveUndo:       nil,
+		EndOfIndexEntries: nil,
+		Extensions:        nil,
+	}
+
+	count := binary.BigEndian.Uint32(data[8:])
+	end := len(data) - hashSize
+	offset := headerSize
+
+	var previous string
+
+	for range count {
+		var e Entry
+
+		e, offset, err = decodeEntry(data[:end], offset, version, hashSize, previous)
+		if err != nil {
+			return nil, err
+		}
+
+		previous = e.Path
+		idx.Entries = append(idx.Entries, e)
+	}
+
+	for offset < end {
+		if offset+extHeaderSize > end {
+			return nil, fmt.Errorf("%w: truncated extension header", ErrMalformedIndex)
+		}
+
+		sig := string(data[offset : offset+4])
+		size := int(binary.BigEndian.Uint32(data[offset+4:]))
+		offset += extHeaderSize
+
+		if offset+size > end {
+			return nil, fmt.Errorf("%w: extension %s overflows", ErrMalformedIndex, sig)
+		}
+
+		err = idx.decodeExtension(sig, data[offset:offset+size], hashSize)
+		if err != nil {
+			return nil, fmt.Errorf("extension %s: %w", sig, err)
+		}
+
+		idx.Extensions = append(idx.Extensions, sig)
+		offset += size
+	}
+
+	return idx, nil
+}
+
+func decodeEntry(data []byte, offset, version, hashSize int, previous string) (Entry, int, error) {
+	start := offset
+	if offset+statSize+hashSize+flagsSize > len(data) {
+		return Entry{}, 0, fmt.Errorf("%w: truncated entry at %d", ErrMalformedIndex, start)
+	}
+
+	e := Entry{
+		Path:         "",
+		Mode:         binary.BigEndian.Uint32(data[offset+modeOffset:]),
+		Stage:        0,
+		Hash:         hex.EncodeToString(data[offset+statSize : offset+statSize+hashSize]),
+		AssumeValid:  false,
+		SkipWorktree: false,
+		IntentToAdd:  false,
+	}
+	offset += statSize + hashSize
+
+	flags := binary.BigEndian.Uint16(data[offset:])
+	offset += flagsSize
+
+	e.AssumeValid = flags&flagAssumeValid != 0
+	e.Stage = int(flags&flagStageMask) >> flagStageShift
+
+	if flags&flagExtended != 0 {
+		if version < extendedVersion || offset+extFlagsSize > len(data) {
+			return Entry{}, 0, fmt.Errorf("%w: unexpected extended flags at %d", ErrMalformedIndex, start)
+		}
+
+		ext := binary.BigEndian.Uint16(data[offset:])
+		offset += extFlagsSize
+
+		e.SkipWorktree = ext&flagSkipWorktree != 0
+		e.IntentToAdd = ext&flagIntentToAdd != 0
+	}
+
+	prefix := ""
+
+	if version == MaxVersion {
+		strip, n := readVarint(data[offset:])
+		if n == 0 || strip > uint64(len(previous)) {
+			return Entry{}, 0, fmt.Errorf("%w: bad path prefix at %d", ErrMalformedIndex, start)
+		}
+
+		prefix = previous[:len(previous)-int(strip)] //nolint:gosec
+		offset += n
+	}
+
+	nul := bytes.IndexByte(data[offset:], 0)
+	if nul < 0 {
+		return Entry{}, 0, fmt.Errorf("%w: unterminated path at %d", ErrMalformedIndex, start)
+	}
+
+	e.Path = prefix + string(data[offset:offset+nul])
+	offset += nul + 1
+
+	if version == MaxVersion {
+		return e, offset, nil
+	}
+
+	// Entries of versions 2 and 3 are padded with NULs to a multiple of
+	// eight bytes, the path terminator included.
+	if rem := (offset - start) % entryAlignment; rem != 0 {
+		offset += entryAlignment - rem
+	}
+
+	return e, offset, nil
+}
+
+func (idx *Index) decodeExtension(sig string, data []byte, hashSize int) error {
+	var err error
+
+	switch sig {
+	case ExtTree:
+		idx.Tree, _, err = decodeTree(data, "", hashSize)
+	case ExtResolveUndo:
+		idx.ResolveUndo, err = decodeResolveUndo(data, hashSize)
+	case ExtEndOfIndexEntries:
+		if len(data) != eoieOffsetSize+hashSize {
+			return fmt.Errorf("%w: bad size %d", ErrMalformedIndex, len(data))
+		}
+
+		idx.EndOfIndexEntries = &EndOfIndexEntries{
+			Offset: binary.BigEndian.Uint32(data),
+			Hash:   hex.EncodeToString(data[eoieOffsetSize:]),
+		}
+	}
+
+	return err
+}
+
+// decodeTree decodes the node at the start of data, followed by its
+// subtrees, returning them in pre-order along with the remaining data.
+func decodeTree(data []byte, parent string, hashSize int) ([]Tree, []byte, error) {
+	nul := bytes.IndexByte(data, 0)
+	if nul < 0 {
+		return nil, nil, fmt.Errorf("%w: unterminated tree path", ErrMalformedIndex)
+	}
+
+	name := string(data[:nul])
+	data = data[nul+1:]
+
+	nl := bytes.IndexByte(data, '\n')
+	if nl < 0 {
+		return nil, nil, fmt.Errorf("%w: unterminated tree counts", ErrMalformedIndex)
+	}
+
+	var entries, subtrees int
+
+	_, err := fmt.Sscanf(string(data[:nl]), "%d %d", &entries, &subtrees)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: tree counts: %w", ErrMalformedIndex, err)
+	}
+
+	data = data[nl+1:]
+
+	node := Tree{Path: joinPath(parent, name), Entries: entries, Subtrees: subtrees, Hash: ""}
+
+	if entries >= 0 {
+		if len(data) < hashSize {
+			return nil, nil, fmt.Errorf("%w: truncated tree hash", ErrMalformedIndex)
+		}
+
+		node.Hash = hex.EncodeToString(data[:hashSize])
+		data = data[hashSize:]
+	}
+
+	nodes := []Tree{node}
+
+	for range subtrees {
+		var children []Tree
+
+		children, data, err = decodeTree(data, node.Path, hashSize)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		nodes = append(nodes, children...)
+	}
+
+	return nodes, data, nil
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "/" + name
+}
+
+func decodeResolveUndo(data []byte, hashSize int) ([]ResolveUndo, error) {
+	var entries []ResolveUndo
+
+	for len(data) > 0 {
+		var (
+			e     ResolveUndo
+			field string
+			err   error
+		)
+
+		e.Path, data, err = cutNul(data)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range stages {
+			field, data, err = cutNul(data)
+			if err != nil {
+				return nil, err
+			}
+
+			mode, err := strconv.ParseUint(field, 8, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%w: resolve undo mode %q", ErrMalformedIndex, field)
+			}
+
+			e.Modes[i] = uint32(mode)
+		}
+
+		for i := range stages {
+			if e.Modes[i] == 0 {
+				continue
+			}
+
+			if len(data) < hashSize {
+				return nil, fmt.Errorf("%w: truncated resolve undo hash", ErrMalformedIndex)
+			}
+
+			e.Hashes[i] = hex.EncodeToString(data[:hashSize])
+			data = data[hashSize:]
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func cutNul(data []byte) (string, []byte, error) {
+	before, after, ok := bytes.Cut(data, []byte{0})
+	if !ok {
+		return "", nil, fmt.Errorf("%w: unterminated string", ErrMalformedIndex)
+	}
+
+	return string(before), after, nil
+}
+
+// readVarint decodes a varint as used by index v4 path compression,
+// returning the value and the number of bytes read, or zero bytes read if
+// buf is truncated.
+func readVarint(buf []byte) (uint64, int) {
+	var v uint64
+
+	for i, b := range buf {
+		if i > 0 {
+			v++
+		}
+
+		v = v<<varintShift | uint64(b&varintValueMask)
+
+		if b&varintContinue == 0 {
+			return v, i + 1
+		}
+	}
+
+	return 0, 0
+}
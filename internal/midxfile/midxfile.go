@@ -0,0 +1,230 @@
+// Package midxfile decodes version 1 multi-pack-index files.
+package midxfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+)
+
+var ErrMalformedMIDX = errors.New("malformed multi-pack-index")
+
+const (
+	// Magic is the signature at the start of multi-pack-index files.
+	Magic = "MIDX"
+	// VersionSupported is the only multi-pack-index version read.
+	VersionSupported = 1
+
+	headerSize     = 12
+	chunkEntrySize = 12
+	fanoutEntries  = 256
+	isLargeOffset  = 0x80000000
+)
+
+// Chunk IDs of multi-pack-index files.
+const (
+	ChunkPackNames      = "PNAM"
+	ChunkOIDFanout      = "OIDF"
+	ChunkOIDLookup      = "OIDL"
+	ChunkObjectOffsets  = "OOFF"
+	ChunkLargeOffsets   = "LOFF"
+	ChunkRevIndex       = "RIDX"
+	ChunkBitmappedPacks = "BTMP"
+)
+
+// hashVersions maps the object id version of the header to object formats.
+//
+//nolint:gochecknoglobals
+var hashVersions = map[byte]string{
+	1: object.FormatSHA1,
+	2: object.FormatSHA256,
+}
+
+// Chunk is an entry of the chunk table.
+type Chunk struct {
+	ID     string
+	Offset int64
+	Size   int64
+}
+
+// Entry is an object recorded in a multi-pack-index.
+type Entry struct {
+	Hash string
+	// Pack is the position of the object's pack in Index.Packs.
+	Pack   uint32
+	Offset int64
+}
+
+// Index is a decoded multi-pack-index file.
+type Index struct {
+	Version      int
+	ObjectFormat string
+	// Packs are the names of the idx files of the packs, in pack order.
+	Packs  []string
+	Chunks []Chunk
+	// Entries are ordered by hash.
+	Entries []Entry
+	// RevIndex is the content of the RIDX chunk: the position in Entries
+	// of each object, in pseudo-pack order. Nil if there is no RIDX chunk.
+	RevIndex []uint32
+	Checksum []byte
+}
+
+// Decode parses a version 1 multi-pack-index file.
+func Decode(data []byte) (*Index, error) {
+	if len(data) < headerSize || string(data[:len(Magic)]) != Magic {
+		return nil, fmt.Errorf("%w: bad signature", ErrMalformedMIDX)
+	}
+
+	if data[4] != VersionSupported {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrMalformedMIDX, data[4])
+	}
+
+	format, ok := hashVersions[data[5]]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown object id version %d", ErrMalformedMIDX, data[5])
+	}
+
+	hashSize, err := object.HashSize(format)
+	if err != nil {
+		return nil, err
+	}
+
+	if data[7] != 0 {
+		return nil, fmt.Errorf("%w: %d base files are not supported", ErrMalformedMIDX, data[7])
+	}
+
+	idx := &Index{
+		Version:      int(data[4]),
+		ObjectFormat: format,
+		Checksum:     data[len(data)-hashSize:],
+	}
+
+	chunks, err := readChunks(data, int(data[6]), len(data)-hashSize)
+	if err != nil {
+		return nil, err
+	}
+
+	idx.Chunks = chunks
+
+	packs := int(binary.BigEndian.Uint32(data[8:]))
+
+	err = idx.decodeChunks(data, packs, hashSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// readChunks reads the chunk table of count chunks, which must lie between
+// the table and end.
+func readChunks(data []byte, count, end int) ([]Chunk, error) {
+	tableEnd := headerSize + (count+1)*chunkEntrySize
+	if tableEnd > end {
+		return nil, fmt.Errorf("%w: truncated chunk table", ErrMalformedMIDX)
+	}
+
+	chunks := make([]Chunk, 0, count)
+
+	for i := range count {
+		entry := data[headerSize+i*chunkEntrySize:]
+		next := data[headerSize+(i+1)*chunkEntrySize:]
+
+		offset := int64(binary.BigEndian.Uint64(entry[4:]))    //nolint:gosec // checked below.
+		nextOffset := int64(binary.BigEndian.Uint64(next[4:])) //nolint:gosec // checked below.
+
+		if offset < int64(tableEnd) || nextOffset < offset || nextOffset > int64(end) {
+			return nil, fmt.Errorf("%w: chunk %q out of range", ErrMalformedMIDX, entry[:4])
+		}
+
+		chunks = append(chunks, Chunk{ID: string(entry[:4]), Offset: offset, Size: nextOffset - offset})
+	}
+
+	return chunks, nil
+}
+
+func (idx *Index) chunk(data []byte, id string) []byte {
+	for _, c := range idx.Chunks {
+		if c.ID == id {
+			return data[c.Offset : c.Offset+c.Size]
+		}
+	}
+
+	return nil
+}
+
+func (idx *Index) decodeChunks(data []byte, packs, hashSize int) error {
+	names := idx.chunk(data, ChunkPackNames)
+	fanout := idx.chunk(data, ChunkOIDFanout)
+	lookup := idx.chunk(data, ChunkOIDLookup)
+	offsets := idx.chunk(data, ChunkObjectOffsets)
+	large := idx.chunk(data, ChunkLargeOffsets)
+	rev := idx.chunk(data, ChunkRevIndex)
+
+	if names == nil || fanout == nil || lookup == nil || offsets == nil {
+		return fmt.Errorf("%w: missing required chunk", ErrMalformedMIDX)
+	}
+
+	for name := range bytes.SplitSeq(names, []byte{0}) {
+		if len(name) > 0 {
+			idx.Packs = append(idx.Packs, string(name))
+		}
+	}
+
+	if len(idx.Packs) != packs {
+		return fmt.Errorf("%w: %d pack names for %d packs", ErrMalformedMIDX, len(idx.Packs), packs)
+	}
+
+	if len(fanout) != fanoutEntries*4 {
+		return fmt.Errorf("%w: bad fanout size %d", ErrMalformedMIDX, len(fanout))
+	}
+
+	count := int(binary.BigEndian.Uint32(fanout[len(fanout)-4:]))
+	if len(lookup) != count*hashSize || len(offsets) != count*8 {
+		return fmt.Errorf("%w: chunks too short for %d objects", ErrMalformedMIDX, count)
+	}
+
+	idx.Entries = make([]Entry, 0, count)
+
+	for i := range count {
+		pack := binary.BigEndian.Uint32(offsets[i*8:])
+		offset := int64(binary.BigEndian.Uint32(offsets[i*8+4:]))
+
+		if offset&isLargeOffset != 0 {
+			j := int(offset &^ isLargeOffset)
+			if (j+1)*8 > len(large) {
+				return fmt.Errorf("%w: large offset %d out of range", ErrMalformedMIDX, j)
+			}
+
+			offset = int64(binary.BigEndian.Uint64(large[j*8:])) //nolint:gosec // offsets fit in int64.
+		}
+
+		if int(pack) >= packs {
+			return fmt.Errorf("%w: pack %d out of range", ErrMalformedMIDX, pack)
+		}
+
+		idx.Entries = append(idx.Entries, Entry{
+			Hash:   hex.EncodeToString(lookup[i*hashSize : (i+1)*hashSize]),
+			Pack:   pack,
+			Offset: offset,
+		})
+	}
+
+	if rev != nil {
+		if len(rev) != count*4 {
+			return fmt.Errorf("%w: bad RIDX size %d", ErrMalformedMIDX, len(rev))
+		}
+
+		idx.RevIndex = make([]uint32, count)
+		for i := range idx.RevIndex {
+			idx.RevIndex[i] = binary.BigEndian.Uint32(rev[i*4:])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package midxfile_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/midxfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// build returns a sha1 multi-pack-index of one pack, p.idx, holding a single
+// object at offset. If large offsets are given, they are written to a LOFF
+// chunk.
+func build(offset uint32, large ...uint64) []byte {
+	const hashSize = 20
+
+	names := []byte("p.idx\x00\x00\x00")
+	fanout := make([]byte, 256*4)
+
+	for i := 0x12; i < 256; i++ {
+		binary.BigEndian.PutUint32(fanout[i*4:], 1)
+	}
+
+	lookup := make([]byte, hashSize)
+	lookup[0] = 0x12
+
+	offsets := binary.BigEndian.AppendUint32(make([]byte, 4), offset)
+
+	chunks := []struct {
+		id   string
+		data []byte
+	}{
+		{midxfile.ChunkPackNames, names},
+		{midxfile.ChunkOIDFanout, fanout},
+		{midxfile.ChunkOIDLookup, lookup},
+		{midxfile.ChunkObjectOffsets, offsets},
+	}
+
+	if len(large) > 0 {
+		var loff []byte
+		for _, o := range large {
+			loff = binary.BigEndian.AppendUint64(loff, o)
+		}
+
+		chunks = append(chunks, struct {
+			id   string
+			data []byte
+		}{midxfile.ChunkLargeOffsets, loff})
+	}
+
+	data := []byte{'M', 'I', 'D', 'X', 1, 1, byte(len(chunks)), 0, 0, 0, 0, 1}
+	pos := uint64(len(data) + (len(chunks)+1)*12)
+
+	for _, c := range chunks {
+		data = append(data, c.id...)
+		data = binary.BigEndian.AppendUint64(data, pos)
+		pos += uint64(len(c.data))
+	}
+
+	data = append(data, 0, 0, 0, 0)
+	data = binary.BigEndian.AppendUint64(data, pos)
+
+	for _, c := range chunks {
+		data = append(data, c.data...)
+	}
+
+	return append(data, make([]byte, hashSize)...)
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	idx, err := midxfile.Decode(build(42))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, idx.Version)
+	assert.Equal(t, "sha1", idx.ObjectFormat)
+	assert.Equal(t, []string{"p.idx"}, idx.Packs)
+	assert.Len(t, idx.Chunks, 4)
+	assert.Nil(t, idx.RevIndex)
+	assert.Equal(t, []midxfile.Entry{
+		{Hash: "1200000000000000000000000000000000000000", Pack: 0, Offset: 42},
+	}, idx.Entries)
+}
+
+func TestDecodeLargeOffset(t *testing.T) {
+	t.Parallel()
+
+	// The offset is the index of the object's offset in LOFF, with the most
+	// significant bit set.
+	idx, err := midxfile.Decode(build(0x80000001, 1, 1<<40))
+	require.NoError(t, err)
+
+	assert.Len(t, idx.Chunks, 5)
+	assert.Equal(t, []midxfile.Entry{
+		{Hash: "1200000000000000000000000000000000000000", Pack: 0, Offset: 1 << 40},
+	}, idx.Entries)
+
+	_, err = midxfile.Decode(build(0x80000002, 1, 1<<40))
+	require.ErrorIs(t, err, midxfile.ErrMalformedMIDX)
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]func([]byte) []byte{
+		"signature":     func(b []byte) []byte { b[0] = 'X'; return b },
+		"version":       func(b []byte) []byte { b[4] = 2; return b },
+		"object format": func(b []byte) []byte { b[5] = 3; return b },
+		"base files":    func(b []byte) []byte { b[7] = 1; return b },
+		"pack count":    func(b []byte) []byte { b[11] = 2; return b },
+		"chunk table":   func(b []byte) []byte { b[6] = 200; return b },
+		"chunk id":      func(b []byte) []byte { b[12] = 'X'; return b },
+		"truncated":     func(b []byte) []byte { return b[:40] },
+		"large offset": func(b []byte) []byte {
+			// The offset is the last 4 bytes of OOFF, before the checksum.
+			b[len(b)-20-4] |= 0x80
+
+			return b
+		},
+	}
+
+	for name, corrupt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := midxfile.Decode(corrupt(build(42)))
+			require.ErrorIs(t, err, midxfile.ErrMalformedMIDX)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrMalformedObject = errors.New("malformed object")
+
+const (
+	secondsPerHour   = 3600
+	secondsPerMinute = 60
+	timezoneLen      = 5
+)
+
+// DecodeCommit parses the canonical content of a commit object. Headers other
+// than tree, parent, author and committer (e.g. gpgsig or mergetag) are
+// skipped.
+func DecodeCommit(content []byte) (Commit, error) {
+	var c Commit
+
+	headers, message, _ := strings.Cut(string(content), "\n\n")
+	c.Message = message
+
+	for line := range strings.SplitSeq(headers, "\n") {
+		// Continuation lines of multi-line headers.
+		if line == "" || line[0] == ' ' {
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+
+		var err error
+
+		switch key {
+		case "tree":
+			c.Tree = value
+		case "parent":
+			c.Parents = append(c.Parents, value)
+		case "author":
+			c.Author, err = ParseSignature(value)
+		case "committer":
+			c.Committer, err = ParseSignature(value)
+		}
+
+		if err != nil {
+			return Commit{}, err
+		}
+	}
+
+	if c.Tree == "" {
+		return Commit{}, fmt.Errorf("%w: commit has no tree", ErrMalformedObject)
+	}
+
+	return c, nil
+}
+
+// ParseSignature parses a signature as encoded in commit and tag headers,
+// the reverse of Signature.String.
+func ParseSignature(s string) (Signature, error) {
+	open := strings.LastIndexByte(s, '<')
+	closing := strings.LastIndexByte(s, '>')
+
+	if open < 0 || closing < open {
+		return Signature{}, fmt.Errorf("%w: signature %q", ErrMalformedObject, s)
+	}
+
+	sig := Signature{
+		Name:  strings.TrimSpace(s[:open]),
+		Email: s[open+1 : closing],
+		When:  time.Time{},
+	}
+
+	fields := strings.Fields(s[closing+1:])
+	if len(fields) != 2 || len(fields[1]) != timezoneLen {
+		return Signature{}, fmt.Errorf("%w: signature date %q", ErrMalformedObject, s)
+	}
+
+	sec, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return Signature{}, fmt.Errorf("%w: signature date %q", ErrMalformedObject, s)
+	}
+
+	hours, errH := strconv.Atoi(fields[1][1:3])
+	minutes, errM := strconv.Atoi(fields[1][3:])
+
+	if errH != nil || errM != nil {
+		return Signature{}, fmt.Errorf("%w: signature timezone %q", ErrMalformedObject, s)
+	}
+
+	offset := hours*secondsPerHour + minutes*secondsPerMinute
+	if fields[1][0] == '-' {
+		offset = -offset
+	}
+
+	sig.When = time.Unix(sec, 0).In(time.FixedZone("", offset))
+
+	return sig, nil
+}
@@ -0,0 +1,132 @@
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Git tree entry modes.
+const (
+	ModeDir        uint32 = 0o040000
+	ModeRegular    uint32 = 0o100644
+	ModeExecutable uint32 = 0o100755
+	ModeSymlink    uint32 = 0o120000
+	ModeSubmodule  uint32 = 0o160000
+)
+
+// TreeEntry is a single entry of a tree object.
+type TreeEntry struct {
+	Name string
+	Mode uint32
+	Hash string
+}
+
+// EncodeTree returns the canonical content of a tree object holding entries.
+// Entries are sorted the way git does, where directories compare as if their
+// name had a trailing slash.
+func EncodeTree(format string, entries []TreeEntry) ([]byte, error) {
+	sorted := slices.Clone(entries)
+	slices.SortFunc(sorted, func(a, b TreeEntry) int {
+		return strings.Compare(treeSortKey(a), treeSortKey(b))
+	})
+
+	var buf bytes.Buffer
+
+	for _, e := range sorted {
+		h, err := DecodeHash(format, e.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.WriteString(strconv.FormatUint(uint64(e.Mode), 8))
+		buf.WriteByte(' ')
+		buf.WriteString(e.Name)
+		buf.WriteByte(0)
+		buf.Write(h)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func treeSortKey(e TreeEntry) string {
+	if e.Mode == ModeDir {
+		return e.Name + "/"
+	}
+
+	return e.Name
+}
+
+// Signature identifies the author, committer or tagger of an object.
+type Signature struct {
+	Name  string
+	Email string
+	When  time.Time
+}
+
+// String returns the signature as encoded in commit and tag headers.
+func (s Signature) String() string {
+	_, offset := s.When.Zone()
+
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+
+	return fmt.Sprintf("%s <%s> %d %c%02d%02d",
+		s.Name, s.Email, s.When.Unix(), sign, offset/3600, (offset%3600)/60)
+}
+
+// Commit holds the fields of a commit object.
+type Commit struct {
+	Tree      string
+	Parents   []string
+	Author    Signature
+	Committer Signature
+	Message   string
+}
+
+// EncodeCommit returns the canonical content of the commit c.
+func EncodeCommit(c Commit) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "tree %s\n", c.Tree)
+
+	for _, p := range c.Parents {
+		fmt.Fprintf(&buf, "parent %s\n", p)
+	}
+
+	fmt.Fprintf(&buf, "author %s\n", c.Author)
+	fmt.Fprintf(&buf, "committer %s\n", c.Committer)
+	buf.WriteByte('\n')
+	buf.WriteString(c.Message)
+
+	return buf.Bytes()
+}
+
+// Tag holds the fields of an annotated tag object.
+type Tag struct {
+	Object  string
+	Type    Type
+	Name    string
+	Tagger  Signature
+	Message string
+}
+
+// EncodeTag returns the canonical content of the annotated tag t.
+func EncodeTag(t Tag) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "object %s\n", t.Object)
+	fmt.Fprintf(&buf, "type %s\n", t.Type)
+	fmt.Fprintf(&buf, "tag %s\n", t.Name)
+	fmt.Fprintf(&buf, "tagger %s\n", t.Tagger)
+	buf.WriteByte('\n')
+	buf.WriteString(t.Message)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,147 @@
+// Package object implements the minimal subset of the git object model
+// required to synthesize and inspect fixtures, without depending on go-git.
+package object
+
+import (
+	"crypto/sha1" //nolint:gosec // sha1 is the default git object format.
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"strconv"
+)
+
+var (
+	ErrUnknownObjectFormat = errors.New("unknown object format")
+	ErrUnknownType         = errors.New("unknown object type")
+	ErrInvalidHash         = errors.New("invalid object hash")
+)
+
+const (
+	FormatSHA1   = "sha1"
+	FormatSHA256 = "sha256"
+)
+
+// Type is the object type as encoded in packfiles. Values match
+// plumbing.ObjectType in go-git.
+type Type int
+
+const (
+	InvalidType Type = 0
+	CommitType  Type = 1
+	TreeType    Type = 2
+	BlobType    Type = 3
+	TagType     Type = 4
+	OFSDelta    Type = 6
+	REFDelta    Type = 7
+)
+
+func (t Type) String() string {
+	switch t {
+	case CommitType:
+		return "commit"
+	case TreeType:
+		return "tree"
+	case BlobType:
+		return "blob"
+	case TagType:
+		return "tag"
+	case OFSDelta:
+		return "ofs-delta"
+	case REFDelta:
+		return "ref-delta"
+	case InvalidType:
+	}
+
+	return "invalid"
+}
+
+// IsDelta reports whether t is one of the delta types.
+func (t Type) IsDelta() bool {
+	return t == OFSDelta || t == REFDelta
+}
+
+// ParseType returns the Type for the loose object type name s.
+func ParseType(s string) (Type, error) {
+	switch s {
+	case "commit":
+		return CommitType, nil
+	case "tree":
+		return TreeType, nil
+	case "blob":
+		return BlobType, nil
+	case "tag":
+		return TagType, nil
+	}
+
+	return InvalidType, fmt.Errorf("%w: %q", ErrUnknownType, s)
+}
+
+// NewHasher returns a new hash.Hash for the given object format.
+func NewHasher(format string) (hash.Hash, error) {
+	switch format {
+	case FormatSHA1:
+		return sha1.New(), nil //nolint:gosec // sha1 is the default git object format.
+	case FormatSHA256:
+		return sha256.New(), nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownObjectFormat, format)
+}
+
+// HashSize returns the size in bytes of a hash in the given object format.
+func HashSize(format string) (int, error) {
+	switch format {
+	case FormatSHA1:
+		return sha1.Size, nil
+	case FormatSHA256:
+		return sha256.Size, nil
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrUnknownObjectFormat, format)
+}
+
+// Header returns the loose object header ("<type> <size>\x00") for an object
+// of type t with size bytes of content.
+func Header(t Type, size int) []byte {
+	h := make([]byte, 0, 16)
+	h = append(h, t.String()...)
+	h = append(h, ' ')
+	h = strconv.AppendInt(h, int64(size), 10)
+
+	return append(h, 0)
+}
+
+// Hash returns the hex-encoded object name of content with type t.
+func Hash(format string, t Type, content []byte) (string, error) {
+	h, err := NewHasher(format)
+	if err != nil {
+		return "", err
+	}
+
+	h.Write(Header(t, len(content)))
+	h.Write(content)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// DecodeHash decodes a hex-encoded object name, validating its length against
+// the object format.
+func DecodeHash(format, s string) ([]byte, error) {
+	size, err := HashSize(format)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s) != size*2 {
+		return nil, fmt.Errorf("%w: %q is not a %s hash", ErrInvalidHash, s, format)
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidHash, err)
+	}
+
+	return b, nil
+}
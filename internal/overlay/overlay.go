@@ -0,0 +1,548 @@
+// Package overlay implements a copy-on-write billy.Filesystem layered over a
+// read-only base filesystem.
+package overlay
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/helper/chroot"
+	"github.com/go-git/go-billy/v6/memfs"
+)
+
+const (
+	dirMode = 0o755
+
+	writeFlags = os.O_WRONLY | os.O_RDWR | os.O_CREATE | os.O_TRUNC | os.O_APPEND
+)
+
+// overlay reads from base until a path is modified, at which point the path
+// is copied up into upper and served from there. Paths removed from base are
+// recorded in deleted, so that they are hidden without modifying base.
+type overlay struct {
+	mu      sync.Mutex
+	base    billy.Filesystem
+	upper   billy.Filesystem
+	deleted map[string]struct{}
+}
+
+// New returns a filesystem holding the contents of base, where changes are
+// kept in memory and never written to base. Many overlays can share the
+// same base, which must not be modified while they are in use.
+func New(base billy.Filesystem) billy.Filesystem {
+	o := &overlay{
+		mu:      sync.Mutex{},
+		base:    base,
+		upper:   memfs.New(),
+		deleted: map[string]struct{}{},
+	}
+
+	// As with memfs, symlinks are resolved and paths kept within the root by
+	// the chroot helper, so the overlay only deals with resolved paths.
+	return chroot.New(o, string(filepath.Separator))
+}
+
+func (o *overlay) Create(filename string) (billy.File, error) {
+	return o.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o666)
+}
+
+func (o *overlay) Open(filename string) (billy.File, error) {
+	return o.OpenFile(filename, os.O_RDONLY, 0)
+}
+
+func (o *overlay) OpenFile(filename string, flag int, perm fs.FileMode) (billy.File, error) {
+	name := clean(filename)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	fi, inUpper, err := o.lstat(name)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	exists := err == nil
+
+	if flag&writeFlags == 0 {
+		if !exists || inUpper {
+			return o.upper.OpenFile(name, flag, perm)
+		}
+
+		return o.base.OpenFile(name, os.O_RDONLY, 0)
+	}
+
+	switch {
+	case exists && flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		return nil, &os.PathError{Op: "open", Path: filename, Err: os.ErrExist}
+	case exists && fi.IsDir():
+		return nil, &os.PathError{Op: "open", Path: filename, Err: syscall.EISDIR}
+	case exists && !inUpper && flag&os.O_TRUNC == 0:
+		err = o.copyUp(name)
+	case exists && !inUpper:
+		// Truncated files are created from scratch, keeping their mode.
+		flag |= os.O_CREATE
+		perm = fi.Mode().Perm()
+		err = o.mkdirs(path.Dir(name))
+	case !exists && flag&os.O_CREATE == 0:
+		return nil, &os.PathError{Op: "open", Path: filename, Err: os.ErrNotExist}
+	case !exists:
+		err = o.mkdirs(path.Dir(name))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return o.upper.OpenFile(name, flag, perm)
+}
+
+func (o *overlay) Stat(filename string) (os.FileInfo, error) {
+	return o.Lstat(filename)
+}
+
+func (o *overlay) Lstat(filename string) (os.FileInfo, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	fi, _, err := o.lstat(clean(filename))
+
+	return fi, err
+}
+
+func (o *overlay) Rename(oldpath, newpath string) error {
+	from, to := clean(oldpath), clean(newpath)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	fi, _, err := o.lstat(from)
+	if err != nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err}
+	}
+
+	tfi, toUpper, err := o.lstat(to)
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	case tfi.IsDir() && !fi.IsDir():
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EISDIR}
+	case tfi.IsDir():
+		entries, err := o.readDir(to)
+		if err != nil {
+			return err
+		}
+
+		if len(entries) > 0 {
+			return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.ENOTEMPTY}
+		}
+
+		if toUpper {
+			err = o.upper.Remove(to)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = o.copyTree(from)
+	if err != nil {
+		return err
+	}
+
+	err = o.mkdirs(path.Dir(to))
+	if err != nil {
+		return err
+	}
+
+	err = o.upper.Rename(from, to)
+	if err != nil {
+		return err
+	}
+
+	return o.hideTree(from)
+}
+
+func (o *overlay) Remove(filename string) error {
+	name := clean(filename)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	fi, inUpper, err := o.lstat(name)
+	if err != nil {
+		return &os.PathError{Op: "remove", Path: filename, Err: err}
+	}
+
+	if fi.IsDir() {
+		entries, err := o.readDir(name)
+		if err != nil {
+			return err
+		}
+
+		if len(entries) > 0 {
+			return &os.PathError{Op: "remove", Path: filename, Err: syscall.ENOTEMPTY}
+		}
+	}
+
+	if inUpper {
+		err = o.upper.Remove(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	o.hide(name)
+
+	return nil
+}
+
+func (o *overlay) Join(elem ...string) string {
+	return filepath.Join(elem...)
+}
+
+func (o *overlay) TempFile(dir, prefix string) (billy.File, error) {
+	name := clean(dir)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	err := o.mkdirs(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.upper.TempFile(name, prefix)
+}
+
+func (o *overlay) ReadDir(dirname string) ([]fs.DirEntry, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.readDir(clean(dirname))
+}
+
+func (o *overlay) MkdirAll(filename string, _ fs.FileMode) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.mkdirs(clean(filename))
+}
+
+func (o *overlay) Symlink(target, link string) error {
+	name := clean(link)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	_, _, err := o.lstat(name)
+	if err == nil {
+		return &os.LinkError{Op: "symlink", Old: target, New: link, Err: os.ErrExist}
+	}
+
+	err = o.mkdirs(path.Dir(name))
+	if err != nil {
+		return err
+	}
+
+	return o.upper.Symlink(target, name)
+}
+
+func (o *overlay) Readlink(link string) (string, error) {
+	name := clean(link)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	_, inUpper, err := o.lstat(name)
+	if err != nil {
+		return "", &os.PathError{Op: "readlink", Path: link, Err: err}
+	}
+
+	if inUpper {
+		return o.upper.Readlink(name)
+	}
+
+	return o.base.Readlink(name)
+}
+
+func (o *overlay) Chmod(filename string, mode fs.FileMode) error {
+	name := clean(filename)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	err := o.copyUp(name)
+	if err != nil {
+		return err
+	}
+
+	return chmod(o.upper, name, mode)
+}
+
+// Capabilities implements billy.Capable.
+func (o *overlay) Capabilities() billy.Capability {
+	return billy.Capabilities(o.upper)
+}
+
+// lstat returns the file info of name and whether it is held by upper.
+func (o *overlay) lstat(name string) (os.FileInfo, bool, error) {
+	fi, err := o.upper.Lstat(name)
+	if err == nil {
+		return fi, true, nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, false, err
+	}
+
+	if _, ok := o.deleted[name]; ok {
+		return nil, false, os.ErrNotExist
+	}
+
+	fi, err = o.base.Lstat(name)
+
+	return fi, false, err
+}
+
+// readDir lists the entries of both layers, upper ones taking precedence.
+func (o *overlay) readDir(name string) ([]fs.DirEntry, error) {
+	fi, inUpper, err := o.lstat(name)
+	if err != nil {
+		return nil, &os.PathError{Op: "readdir", Path: name, Err: err}
+	}
+
+	if !fi.IsDir() {
+		return nil, &os.PathError{Op: "readdir", Path: name, Err: syscall.ENOTDIR}
+	}
+
+	var entries []fs.DirEntry
+
+	seen := map[string]struct{}{}
+
+	if inUpper {
+		entries, err = o.upper.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			seen[e.Name()] = struct{}{}
+		}
+	}
+
+	base, err := o.base.ReadDir(name)
+	if err != nil {
+		// name is a directory in upper only.
+		base = nil
+	}
+
+	for _, e := range base {
+		child := path.Join(name, e.Name())
+		if _, ok := o.deleted[child]; ok {
+			continue
+		}
+
+		if _, ok := seen[e.Name()]; ok {
+			continue
+		}
+
+		entries = append(entries, e)
+	}
+
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return entries, nil
+}
+
+// mkdirs creates name and its parents in upper, copying up the directories
+// already present in base so that their modes are kept.
+func (o *overlay) mkdirs(name string) error {
+	if name == "/" {
+		return nil
+	}
+
+	err := o.mkdirs(path.Dir(name))
+	if err != nil {
+		return err
+	}
+
+	fi, inUpper, err := o.lstat(name)
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return o.upper.MkdirAll(name, dirMode)
+	case err != nil:
+		return err
+	case !fi.IsDir():
+		return &os.PathError{Op: "mkdir", Path: name, Err: syscall.ENOTDIR}
+	case inUpper:
+		return nil
+	}
+
+	return o.copyUp(name)
+}
+
+// copyUp copies name from base into upper, without the contents of
+// directories. It does nothing if name is already held by upper.
+func (o *overlay) copyUp(name string) error {
+	fi, inUpper, err := o.lstat(name)
+	if err != nil {
+		return err
+	}
+
+	if inUpper {
+		return nil
+	}
+
+	err = o.mkdirs(path.Dir(name))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case fi.IsDir():
+		err = o.upper.MkdirAll(name, fi.Mode().Perm())
+	case fi.Mode()&os.ModeSymlink != 0:
+		var target string
+
+		target, err = o.base.Readlink(name)
+		if err == nil {
+			err = o.upper.Symlink(target, name)
+		}
+
+		return err
+	default:
+		err = o.copyFile(name, fi.Mode().Perm())
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return chmod(o.upper, name, fi.Mode().Perm())
+}
+
+// copyTree copies name from base into upper along with all its contents.
+func (o *overlay) copyTree(name string) error {
+	err := o.copyUp(name)
+	if err != nil {
+		return err
+	}
+
+	fi, _, err := o.lstat(name)
+	if err != nil || !fi.IsDir() {
+		return err
+	}
+
+	entries, err := o.readDir(name)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		err = o.copyTree(path.Join(name, e.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *overlay) copyFile(name string, perm fs.FileMode) (err error) {
+	src, err := o.base.Open(name)
+	if err != nil {
+		return err
+	}
+
+	defer src.Close()
+
+	dst, err := o.upper.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		errClose := dst.Close()
+		if err == nil {
+			err = errClose
+		}
+	}()
+
+	_, err = io.Copy(dst, src)
+
+	return err
+}
+
+// hide hides name from base, if present there.
+func (o *overlay) hide(name string) {
+	if _, err := o.base.Lstat(name); err == nil {
+		o.deleted[name] = struct{}{}
+	}
+}
+
+// hideTree hides name and all its contents from base.
+func (o *overlay) hideTree(name string) error {
+	fi, err := o.base.Lstat(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	o.deleted[name] = struct{}{}
+
+	if !fi.IsDir() {
+		return nil
+	}
+
+	entries, err := o.base.ReadDir(name)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		err = o.hideTree(path.Join(name, e.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chmod sets the permissions of name in fs. memfs replaces the whole mode,
+// so the file type is kept explicitly.
+func chmod(fs billy.Filesystem, name string, mode fs.FileMode) error {
+	c, ok := fs.(billy.Chmod)
+	if !ok {
+		return billy.ErrNotSupported
+	}
+
+	fi, err := fs.Lstat(name)
+	if err != nil {
+		return err
+	}
+
+	return c.Chmod(name, fi.Mode().Type()|mode.Perm())
+}
+
+// clean returns name as an absolute slash-separated path.
+func clean(name string) string {
+	return path.Clean("/" + filepath.ToSlash(name))
+}
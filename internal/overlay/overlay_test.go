@@ -0,0 +1,238 @@
+package overlay_test
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/memfs"
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git-fixtures/v6/internal/overlay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBase returns a base filesystem holding:
+//
+//	README
+//	run.sh (0o755)
+//	dir/file
+//	dir/sub/nested
+//	link -> dir/file
+func newBase(t *testing.T) billy.Filesystem {
+	t.Helper()
+
+	base := memfs.New()
+	require.NoError(t, util.WriteFile(base, "README", []byte("readme"), 0o644))
+	require.NoError(t, util.WriteFile(base, "run.sh", []byte("#!/bin/sh"), 0o755))
+	require.NoError(t, util.WriteFile(base, "dir/file", []byte("file"), 0o644))
+	require.NoError(t, util.WriteFile(base, "dir/sub/nested", []byte("nested"), 0o644))
+	require.NoError(t, base.Symlink("dir/file", "link"))
+
+	return base
+}
+
+func names(t *testing.T, fs billy.Filesystem, dir string) []string {
+	t.Helper()
+
+	entries, err := fs.ReadDir(dir)
+	require.NoError(t, err)
+
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.Name())
+	}
+
+	return out
+}
+
+func assertContent(t *testing.T, fs billy.Filesystem, name, want string) {
+	t.Helper()
+
+	content, err := util.ReadFile(fs, name)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(content))
+}
+
+func TestReadsFromBase(t *testing.T) {
+	t.Parallel()
+
+	fs := overlay.New(newBase(t))
+
+	assertContent(t, fs, "README", "readme")
+	assertContent(t, fs, "dir/sub/nested", "nested")
+	assertContent(t, fs, "link", "file")
+	assert.Equal(t, []string{"README", "dir", "link", "run.sh"}, names(t, fs, ""))
+
+	fi, err := fs.Stat("run.sh")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
+
+	target, err := fs.Readlink("link")
+	require.NoError(t, err)
+	assert.Equal(t, "dir/file", target)
+}
+
+func TestWritesDoNotReachBase(t *testing.T) {
+	t.Parallel()
+
+	base := newBase(t)
+	a := overlay.New(base)
+	b := overlay.New(base)
+
+	f, err := a.OpenFile("README", os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte(" changed"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	require.NoError(t, util.WriteFile(a, "dir/new", []byte("new"), 0o644))
+	require.NoError(t, a.Remove("dir/sub/nested"))
+
+	assertContent(t, a, "README", "readme changed")
+	assertContent(t, a, "dir/new", "new")
+	assert.Equal(t, []string{"file", "new", "sub"}, names(t, a, "dir"))
+	assert.Empty(t, names(t, a, "dir/sub"))
+
+	for _, fs := range []billy.Filesystem{base, b} {
+		assertContent(t, fs, "README", "readme")
+		assertContent(t, fs, "dir/sub/nested", "nested")
+
+		_, err = fs.Stat("dir/new")
+		require.ErrorIs(t, err, os.ErrNotExist)
+	}
+}
+
+func TestSymlinkFollowsUpper(t *testing.T) {
+	t.Parallel()
+
+	fs := overlay.New(newBase(t))
+	require.NoError(t, util.WriteFile(fs, "dir/file", []byte("changed"), 0o644))
+
+	assertContent(t, fs, "link", "changed")
+}
+
+func TestTruncateKeepsMode(t *testing.T) {
+	t.Parallel()
+
+	fs := overlay.New(newBase(t))
+	f, err := fs.OpenFile("run.sh", os.O_WRONLY|os.O_TRUNC, 0o600)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	fi, err := fs.Stat("run.sh")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
+	assert.Zero(t, fi.Size())
+}
+
+func TestCreateExclusive(t *testing.T) {
+	t.Parallel()
+
+	fs := overlay.New(newBase(t))
+	_, err := fs.OpenFile("README", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	require.ErrorIs(t, err, os.ErrExist)
+}
+
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	fs := overlay.New(newBase(t))
+
+	err := fs.Remove("dir")
+	require.Error(t, err)
+
+	require.NoError(t, util.RemoveAll(fs, "dir"))
+
+	_, err = fs.Stat("dir/file")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.Equal(t, []string{"README", "link", "run.sh"}, names(t, fs, ""))
+
+	// Recreating a removed directory does not bring back its contents.
+	require.NoError(t, fs.MkdirAll("dir", 0o755))
+	assert.Empty(t, names(t, fs, "dir"))
+}
+
+func TestRenameDir(t *testing.T) {
+	t.Parallel()
+
+	fs := overlay.New(newBase(t))
+	require.NoError(t, fs.Rename("dir", "moved"))
+
+	assertContent(t, fs, "moved/file", "file")
+	assertContent(t, fs, "moved/sub/nested", "nested")
+
+	_, err := fs.Stat("dir")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.Equal(t, []string{"README", "link", "moved", "run.sh"}, names(t, fs, ""))
+}
+
+func TestRenameOverBaseFile(t *testing.T) {
+	t.Parallel()
+
+	fs := overlay.New(newBase(t))
+	require.NoError(t, fs.Rename("dir/file", "README"))
+
+	assertContent(t, fs, "README", "file")
+
+	_, err := fs.Stat("dir/file")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestChmod(t *testing.T) {
+	t.Parallel()
+
+	base := newBase(t)
+	fs := overlay.New(base)
+
+	c, ok := fs.(billy.Chmod)
+	require.True(t, ok)
+	require.NoError(t, c.Chmod("README", 0o600))
+
+	fi, err := fs.Stat("README")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	assertContent(t, fs, "README", "readme")
+
+	fi, err = base.Stat("README")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o644), fi.Mode().Perm())
+}
+
+func TestChroot(t *testing.T) {
+	t.Parallel()
+
+	fs, err := overlay.New(newBase(t)).Chroot("dir")
+	require.NoError(t, err)
+
+	assertContent(t, fs, "sub/nested", "nested")
+	require.NoError(t, util.WriteFile(fs, "file", []byte("changed"), 0o644))
+	assertContent(t, fs, "file", "changed")
+}
+
+func TestConcurrentOverlays(t *testing.T) {
+	t.Parallel()
+
+	base := newBase(t)
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Go(func() {
+			fs := overlay.New(base)
+
+			assert.NoError(t, util.WriteFile(fs, "README", []byte("changed"), 0o644))
+			assert.NoError(t, util.RemoveAll(fs, "dir"))
+
+			content, err := util.ReadFile(fs, "README")
+			assert.NoError(t, err)
+			assert.Equal(t, "changed", string(content))
+		})
+	}
+
+	wg.Wait()
+
+	assertContent(t, base, "README", "readme")
+	assertContent(t, base, "dir/file", "file")
+}
@@ -0,0 +1,125 @@
+package packfile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+)
+
+var ErrMissingBase = errors.New("delta base not found in packfile")
+
+// ResolvedObject is a fully resolved object of a packfile.
+type ResolvedObject struct {
+	// Entry describes the object as stored. Its Hash is always set.
+	Entry
+
+	// ResolvedType is the type of the object once deltas are applied.
+	ResolvedType object.Type
+	// Content is the canonical uncompressed content of the object.
+	Content []byte
+	// Base is the hash of the delta base, for deltified objects.
+	Base string
+	// Depth is the length of the delta chain leading to the object, zero for
+	// non-delta objects.
+	Depth int
+}
+
+// Decode reads every object of the packfile data, resolving deltas. Objects
+// are returned in stream order.
+//
+// Thin packs, whose deltas reference objects outside of the pack, cannot be
+// decoded and fail with ErrMissingBase.
+func Decode(format string, data []byte) ([]ResolvedObject, error) {
+	raw, err := ScanRaw(format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]ResolvedObject, len(raw))
+	byOffset := make(map[int64]int, len(raw))
+	byHash := make(map[string]int, len(raw))
+	resolved := make([]bool, len(raw))
+	pending := 0
+
+	for i, r := range raw {
+		objects[i] = ResolvedObject{Entry: r.Entry, ResolvedType: r.Type, Content: r.Data, Base: "", Depth: 0}
+		byOffset[r.Offset] = i
+
+		if r.Type.IsDelta() {
+			pending++
+
+			continue
+		}
+
+		byHash[r.Hash] = i
+		resolved[i] = true
+	}
+
+	// Deltas may reference bases that are themselves deltas, in any order,
+	// so keep resolving until no further progress can be made.
+	for pending > 0 {
+		progress := false
+
+		for i := range objects {
+			if resolved[i] {
+				continue
+			}
+
+			base, ok := findBase(objects[i].Entry, byOffset, byHash)
+			if !ok || !resolved[base] {
+				continue
+			}
+
+			err := resolve(format, &objects[i], &objects[base])
+			if err != nil {
+				return nil, err
+			}
+
+			byHash[objects[i].Hash] = i
+			resolved[i] = true
+			progress = true
+			pending--
+		}
+
+		if !progress {
+			break
+		}
+	}
+
+	for i, o := range objects {
+		if !resolved[i] {
+			return nil, fmt.Errorf("%w: object at %d", ErrMissingBase, o.Offset)
+		}
+	}
+
+	return objects, nil
+}
+
+func findBase(e Entry, byOffset map[int64]int, byHash map[string]int) (int, bool) {
+	if e.Type == object.OFSDelta {
+		i, ok := byOffset[e.OffsetReference]
+
+		return i, ok
+	}
+
+	i, ok := byHash[e.Reference]
+
+	return i, ok
+}
+
+func resolve(format string, obj, base *ResolvedObject) error {
+	content, err := Patch(base.Content, obj.Content)
+	if err != nil {
+		return fmt.Errorf("object at %d: %w", obj.Offset, err)
+	}
+
+	obj.Content = content
+	obj.ResolvedType = base.ResolvedType
+	obj.Base = base.Hash
+	obj.Depth = base.Depth + 1
+
+	obj.Hash, err = object.Hash(format, obj.ResolvedType, content)
+
+	return err
+}
@@ -0,0 +1,229 @@
+package packfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidDelta = errors.New("invalid delta")
+
+const (
+	deltaBlockSize   = 16
+	maxCopySize      = 0x10000
+	maxInsertSize    = 0x7f
+	copyInstruction  = 0x80
+	maxVarintShift   = 63
+	varintContinue   = 0x80
+	varintValueMask  = 0x7f
+	copyOffsetBytes  = 4
+	copySizeBytes    = 3
+	copySizeBitShift = 4
+)
+
+// Diff returns a git delta that transforms base into target.
+//
+// The algorithm indexes base in fixed-size blocks and greedily extends
+// matches found in target. It favours determinism and simplicity over
+// compression ratio.
+func Diff(base, target []byte) []byte {
+	var out bytes.Buffer
+
+	out.Write(appendVarint(nil, uint64(len(base))))
+	out.Write(appendVarint(nil, uint64(len(target))))
+
+	index := make(map[string]int, len(base)/deltaBlockSize)
+	for i := 0; i+deltaBlockSize <= len(base); i += deltaBlockSize {
+		key := string(base[i : i+deltaBlockSize])
+		if _, ok := index[key]; !ok {
+			index[key] = i
+		}
+	}
+
+	var pending []byte
+
+	for i := 0; i < len(target); {
+		if i+deltaBlockSize <= len(target) {
+			if off, ok := index[string(target[i:i+deltaBlockSize])]; ok {
+				n := deltaBlockSize
+				for off+n < len(base) && i+n < len(target) && base[off+n] == target[i+n] {
+					n++
+				}
+
+				writeInsert(&out, pending)
+				pending = pending[:0]
+
+				writeCopy(&out, off, n)
+				i += n
+
+				continue
+			}
+		}
+
+		pending = append(pending, target[i])
+		i++
+	}
+
+	writeInsert(&out, pending)
+
+	return out.Bytes()
+}
+
+// Patch applies delta to base and returns the resulting target.
+func Patch(base, delta []byte) ([]byte, error) {
+	srcSize, delta, err := readVarint(delta)
+	if err != nil {
+		return nil, err
+	}
+
+	if srcSize != uint64(len(base)) {
+		return nil, fmt.Errorf("%w: base size %d does not match %d", ErrInvalidDelta, len(base), srcSize)
+	}
+
+	dstSize, delta, err := readVarint(delta)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, dstSize)
+
+	for len(delta) > 0 {
+		cmd := delta[0]
+		delta = delta[1:]
+
+		switch {
+		case cmd&copyInstruction != 0:
+			var off, size uint64
+
+			for i := range copyOffsetBytes + copySizeBytes {
+				if cmd&(1<<i) == 0 {
+					continue
+				}
+
+				if len(delta) == 0 {
+					return nil, fmt.Errorf("%w: truncated copy instruction", ErrInvalidDelta)
+				}
+
+				if i < copyOffsetBytes {
+					off |= uint64(delta[0]) << (8 * i)
+				} else {
+					size |= uint64(delta[0]) << (8 * (i - copyOffsetBytes))
+				}
+
+				delta = delta[1:]
+			}
+
+			if size == 0 {
+				size = maxCopySize
+			}
+
+			if off+size > uint64(len(base)) {
+				return nil, fmt.Errorf("%w: copy out of bounds", ErrInvalidDelta)
+			}
+
+			out = append(out, base[off:off+size]...)
+		case cmd != 0:
+			if int(cmd) > len(delta) {
+				return nil, fmt.Errorf("%w: truncated insert instruction", ErrInvalidDelta)
+			}
+
+			out = append(out, delta[:cmd]...)
+			delta = delta[cmd:]
+		default:
+			return nil, fmt.Errorf("%w: reserved instruction", ErrInvalidDelta)
+		}
+	}
+
+	if uint64(len(out)) != dstSize {
+		return nil, fmt.Errorf("%w: result size %d does not match %d", ErrInvalidDelta, len(out), dstSize)
+	}
+
+	return out, nil
+}
+
+// DeltaSizes returns the base and target sizes encoded in the delta header.
+func DeltaSizes(delta []byte) (uint64, uint64, error) {
+	src, rest, err := readVarint(delta)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	dst, _, err := readVarint(rest)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return src, dst, nil
+}
+
+func writeInsert(out *bytes.Buffer, data []byte) {
+	for len(data) > 0 {
+		n := min(len(data), maxInsertSize)
+		out.WriteByte(byte(n))
+		out.Write(data[:n])
+		data = data[n:]
+	}
+}
+
+func writeCopy(out *bytes.Buffer, off, size int) {
+	for size > 0 {
+		n := min(size, maxCopySize)
+
+		cmd := byte(copyInstruction)
+		args := make([]byte, 0, copyOffsetBytes+copySizeBytes)
+
+		for i := range copyOffsetBytes {
+			if b := byte(off >> (8 * i)); b != 0 {
+				cmd |= 1 << i
+				args = append(args, b)
+			}
+		}
+
+		// A size of maxCopySize is encoded as zero, by omitting all bytes.
+		if n != maxCopySize {
+			for i := range copySizeBytes {
+				if b := byte(n >> (8 * i)); b != 0 {
+					cmd |= 1 << (copySizeBitShift + i)
+					args = append(args, b)
+				}
+			}
+		}
+
+		out.WriteByte(cmd)
+		out.Write(args)
+
+		off += n
+		size -= n
+	}
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= varintContinue {
+		b = append(b, byte(v)|varintContinue)
+		v >>= 7
+	}
+
+	return append(b, byte(v))
+}
+
+func readVarint(b []byte) (uint64, []byte, error) {
+	var (
+		v     uint64
+		shift uint
+	)
+
+	for i, c := range b {
+		if shift > maxVarintShift {
+			break
+		}
+
+		v |= uint64(c&varintValueMask) << shift
+		if c&varintContinue == 0 {
+			return v, b[i+1:], nil
+		}
+
+		shift += 7
+	}
+
+	return 0, nil, fmt.Errorf("%w: malformed size header", ErrInvalidDelta)
+}
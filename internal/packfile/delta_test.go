@@ -0,0 +1,61 @@
+package packfile_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/packfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffPatch(t *testing.T) {
+	t.Parallel()
+
+	long := bytes.Repeat([]byte("0123456789abcdef"), 0x2000)
+
+	tests := []struct {
+		name   string
+		base   []byte
+		target []byte
+	}{
+		{name: "empty", base: nil, target: nil},
+		{name: "from empty", base: nil, target: []byte("hello world")},
+		{name: "to empty", base: []byte("hello world"), target: nil},
+		{name: "append", base: long[:100], target: append(long[:100:100], "tail"...)},
+		{name: "prepend", base: long[:100], target: append([]byte("head"), long[:100]...)},
+		{name: "large copy", base: long, target: append(long[:len(long):len(long)], long...)},
+		{name: "unrelated", base: []byte("aaaaaaaaaaaaaaaaaaaaaaa"), target: bytes.Repeat([]byte("b"), 300)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			delta := packfile.Diff(tc.base, tc.target)
+
+			src, dst, err := packfile.DeltaSizes(delta)
+			require.NoError(t, err)
+			assert.EqualValues(t, len(tc.base), src)
+			assert.EqualValues(t, len(tc.target), dst)
+
+			got, err := packfile.Patch(tc.base, delta)
+			require.NoError(t, err)
+			assert.Equal(t, len(tc.target), len(got))
+			assert.True(t, bytes.Equal(tc.target, got))
+		})
+	}
+}
+
+func TestPatchErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := packfile.Patch([]byte("abc"), []byte{0x04, 0x03})
+	require.ErrorIs(t, err, packfile.ErrInvalidDelta)
+
+	_, err = packfile.Patch([]byte("abc"), []byte{0x03, 0x03, 0x91, 0x00, 0x10})
+	require.ErrorIs(t, err, packfile.ErrInvalidDelta)
+
+	_, err = packfile.Patch([]byte("abc"), []byte{0x03, 0x03, 0x00})
+	require.ErrorIs(t, err, packfile.ErrInvalidDelta)
+}
@@ -0,0 +1,220 @@
+package packfile
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+)
+
+var ErrMalformedPack = errors.New("malformed packfile")
+
+const (
+	headerSize     = 12
+	objectTypeMask = 0x07
+)
+
+// Header is the fixed-size header at the start of a packfile.
+type Header struct {
+	Version uint32
+	Objects uint32
+}
+
+// ReadHeader parses the header of the packfile data.
+func ReadHeader(data []byte) (Header, error) {
+	if len(data) < headerSize || string(data[:len(Signature)]) != Signature {
+		return Header{}, fmt.Errorf("%w: bad signature", ErrMalformedPack)
+	}
+
+	h := Header{
+		Version: binary.BigEndian.Uint32(data[4:]),
+		Objects: binary.BigEndian.Uint32(data[8:]),
+	}
+
+	if h.Version != VersionSupported {
+		return Header{}, fmt.Errorf("%w: unsupported version %d", ErrMalformedPack, h.Version)
+	}
+
+	return h, nil
+}
+
+// RawObject is an object as stored in a packfile, with its data inflated.
+type RawObject struct {
+	Entry
+
+	// Data is the inflated stored data: the object content, or the delta
+	// instructions for deltas.
+	Data []byte
+	// End is the offset right after the object's compressed data.
+	End int64
+}
+
+// Scan walks every object of the packfile data in stream order. The Hash of
+// the returned entries is only set for non-delta objects, as resolving deltas
+// may require objects outside of the pack.
+func Scan(format string, data []byte) ([]Entry, error) {
+	objects, err := ScanRaw(format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(objects))
+	for _, obj := range objects {
+		entries = append(entries, obj.Entry)
+	}
+
+	return entries, nil
+}
+
+// ScanRaw is like Scan, but also returns the inflated data of each object.
+func ScanRaw(format string, data []byte) ([]RawObject, error) {
+	hashSize, err := object.HashSize(format)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := ReadHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < headerSize+hashSize {
+		return nil, fmt.Errorf("%w: missing trailer", ErrMalformedPack)
+	}
+
+	objects := make([]RawObject, 0, header.Objects)
+	end := int64(len(data) - hashSize)
+	offset := int64(headerSize)
+
+	for range header.Objects {
+		obj, err := ReadObject(data[:end], offset, hashSize)
+		if err != nil {
+			return nil, err
+		}
+
+		if !obj.Type.IsDelta() {
+			obj.Hash, err = object.Hash(format, obj.Type, obj.Data)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		objects = append(objects, *obj)
+		offset = obj.End
+	}
+
+	if offset != end {
+		return nil, fmt.Errorf("%w: %d trailing bytes after last object", ErrMalformedPack, end-offset)
+	}
+
+	return objects, nil
+}
+
+// ReadObject reads the object stored at offset in the packfile data. The
+// returned object has no Hash set.
+func ReadObject(data []byte, offset int64, hashSize int) (*RawObject, error) {
+	if offset < headerSize || offset >= int64(len(data)) {
+		return nil, fmt.Errorf("%w: offset %d out of range", ErrMalformedPack, offset)
+	}
+
+	pos := offset
+
+	next := func() (byte, error) {
+		if pos >= int64(len(data)) {
+			return 0, fmt.Errorf("%w: truncated object header at %d", ErrMalformedPack, offset)
+		}
+
+		c := data[pos]
+		pos++
+
+		return c, nil
+	}
+
+	c, err := next()
+	if err != nil {
+		return nil, err
+	}
+
+	obj := &RawObject{
+		Entry: Entry{
+			Type:            object.Type((c >> objectTypeShift) & objectTypeMask),
+			Offset:          offset,
+			Size:            int64(c & objectSizeLowMask),
+			Hash:            "",
+			Reference:       "",
+			OffsetReference: 0,
+			CRC32:           0,
+		},
+		Data: nil,
+		End:  0,
+	}
+
+	for shift := objectSizeLowBits; c&varintContinue != 0; shift += 7 {
+		c, err = next()
+		if err != nil {
+			return nil, err
+		}
+
+		obj.Size |= int64(c&varintValueMask) << shift
+	}
+
+	switch obj.Type {
+	case object.CommitType, object.TreeType, object.BlobType, object.TagType:
+	case object.OFSDelta:
+		c, err = next()
+		if err != nil {
+			return nil, err
+		}
+
+		rel := int64(c & varintValueMask)
+		for c&varintContinue != 0 {
+			c, err = next()
+			if err != nil {
+				return nil, err
+			}
+
+			rel = (rel+1)<<7 | int64(c&varintValueMask)
+		}
+
+		obj.OffsetReference = offset - rel
+	case object.REFDelta:
+		if pos+int64(hashSize) > int64(len(data)) {
+			return nil, fmt.Errorf("%w: truncated ref-delta at %d", ErrMalformedPack, offset)
+		}
+
+		obj.Reference = hex.EncodeToString(data[pos : pos+int64(hashSize)])
+		pos += int64(hashSize)
+	case object.InvalidType:
+		fallthrough
+	default:
+		return nil, fmt.Errorf("%w: %w %d at %d", ErrMalformedPack, object.ErrUnknownType, obj.Type, offset)
+	}
+
+	r := bytes.NewReader(data[pos:])
+
+	zr, err := zlib.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: object at %d: %w", ErrMalformedPack, offset, err)
+	}
+
+	obj.Data, err = io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: object at %d: %w", ErrMalformedPack, offset, err)
+	}
+
+	if int64(len(obj.Data)) != obj.Size {
+		return nil, fmt.Errorf("%w: object at %d inflates to %d bytes, expected %d",
+			ErrMalformedPack, offset, len(obj.Data), obj.Size)
+	}
+
+	obj.End = int64(len(data)) - int64(r.Len())
+	obj.CRC32 = crc32.ChecksumIEEE(data[offset:obj.End])
+
+	return obj, nil
+}
@@ -0,0 +1,262 @@
+package packfile
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"slices"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+)
+
+var (
+	ErrDeltaBeforeBase = errors.New("delta before base requires ref-delta")
+	ErrNegativeDepth   = errors.New("negative delta depth")
+)
+
+const (
+	// Signature is the magic at the start of every packfile.
+	Signature = "PACK"
+	// VersionSupported is the only packfile version written and read.
+	VersionSupported uint32 = 2
+	// DefaultMaxDepth is the maximum length of delta chains used by git.
+	DefaultMaxDepth = 50
+
+	objectTypeShift    = 4
+	objectSizeLowMask  = 0x0f
+	objectSizeLowBits  = 4
+	offsetEncodingSize = 10
+)
+
+// DeltaStrategy selects how objects are deltified when written.
+type DeltaStrategy int
+
+const (
+	// DeltaNone writes every object in full.
+	DeltaNone DeltaStrategy = iota
+	// DeltaOFS writes deltas referencing their base by pack offset.
+	DeltaOFS
+	// DeltaREF writes deltas referencing their base by object hash.
+	DeltaREF
+)
+
+// Object is an object to be written into a packfile.
+type Object struct {
+	Type    object.Type
+	Hash    string
+	Content []byte
+}
+
+// WriterOptions configures Encode.
+type WriterOptions struct {
+	Delta DeltaStrategy
+	// MaxDepth is the maximum length of a delta chain, see DefaultMaxDepth.
+	// Zero stores every object in full.
+	MaxDepth int
+	// DeltaBeforeBase writes deltas ahead of their bases. Only valid with
+	// DeltaREF.
+	DeltaBeforeBase bool
+}
+
+// Entry describes an object as stored in a packfile.
+type Entry struct {
+	// Type is the type as stored, which is a delta type for deltified objects.
+	Type object.Type
+	// Offset is the offset of the object header in the pack.
+	Offset int64
+	// Size is the inflated size of the stored data (the delta, for deltas).
+	Size int64
+	// Hash is the hash of the object. Encode sets it for every object, while
+	// Scan only sets it for non-delta objects.
+	Hash string
+	// Reference is the hash of the base object of a ref-delta.
+	Reference string
+	// OffsetReference is the offset of the base object of an ofs-delta.
+	OffsetReference int64
+	// CRC32 is the checksum of the raw stored bytes of the object.
+	CRC32 uint32
+}
+
+// Pack is the result of Encode.
+type Pack struct {
+	Data     []byte
+	Checksum []byte
+	// Entries are ordered by offset.
+	Entries []Entry
+}
+
+// Encode writes objects, in the given order, into a version 2 packfile.
+//
+// When deltas are enabled, each object is deltified against the closest
+// preceding object of the same type, as long as the resulting chain does not
+// exceed MaxDepth.
+func Encode(format string, objects []Object, opts WriterOptions) (*Pack, error) {
+	if opts.DeltaBeforeBase && opts.Delta != DeltaREF {
+		return nil, ErrDeltaBeforeBase
+	}
+
+	if opts.MaxDepth < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrNegativeDepth, opts.MaxDepth)
+	}
+
+	bases := chooseBases(objects, opts.Delta, opts.MaxDepth)
+
+	order := make([]int, len(objects))
+	for i := range order {
+		order[i] = i
+	}
+
+	if opts.DeltaBeforeBase {
+		slices.Reverse(order)
+	}
+
+	hasher, err := object.NewHasher(format)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(Signature)
+	_ = binary.Write(&buf, binary.BigEndian, VersionSupported)
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(objects)))
+
+	offsets := make([]int64, len(objects))
+	entries := make([]Entry, 0, len(objects))
+
+	for _, i := range order {
+		obj := objects[i]
+		offset := int64(buf.Len())
+		offsets[i] = offset
+
+		entry := Entry{
+			Type:            obj.Type,
+			Offset:          offset,
+			Size:            int64(len(obj.Content)),
+			Hash:            obj.Hash,
+			Reference:       "",
+			OffsetReference: 0,
+			CRC32:           0,
+		}
+
+		data := obj.Content
+
+		var raw []byte
+
+		if base := bases[i]; base >= 0 {
+			data = Diff(objects[base].Content, obj.Content)
+			entry.Size = int64(len(data))
+
+			if opts.Delta == DeltaOFS {
+				entry.Type = object.OFSDelta
+				entry.OffsetReference = offsets[base]
+				raw = appendObjectHeader(raw, entry.Type, uint64(len(data)))
+				raw = appendOffset(raw, uint64(offset-offsets[base]))
+			} else {
+				entry.Type = object.REFDelta
+				entry.Reference = objects[base].Hash
+
+				ref, err := hex.DecodeString(entry.Reference)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %w", object.ErrInvalidHash, err)
+				}
+
+				raw = appendObjectHeader(raw, entry.Type, uint64(len(data)))
+				raw = append(raw, ref...)
+			}
+		} else {
+			raw = appendObjectHeader(raw, entry.Type, uint64(len(data)))
+		}
+
+		raw, err = appendCompressed(raw, data)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.CRC32 = crc32.ChecksumIEEE(raw)
+		entries = append(entries, entry)
+
+		buf.Write(raw)
+	}
+
+	hasher.Write(buf.Bytes())
+	checksum := hasher.Sum(nil)
+	buf.Write(checksum)
+
+	return &Pack{
+		Data:     buf.Bytes(),
+		Checksum: checksum,
+		Entries:  entries,
+	}, nil
+}
+
+func chooseBases(objects []Object, strategy DeltaStrategy, maxDepth int) []int {
+	bases := make([]int, len(objects))
+	depths := make([]int, len(objects))
+	last := map[object.Type]int{}
+
+	for i, obj := range objects {
+		bases[i] = -1
+
+		if strategy != DeltaNone {
+			if prev, ok := last[obj.Type]; ok && depths[prev] < maxDepth {
+				bases[i] = prev
+				depths[i] = depths[prev] + 1
+			}
+		}
+
+		last[obj.Type] = i
+	}
+
+	return bases
+}
+
+func appendObjectHeader(b []byte, t object.Type, size uint64) []byte {
+	c := byte(t)<<objectTypeShift | byte(size&objectSizeLowMask)
+	size >>= objectSizeLowBits
+
+	for size != 0 {
+		b = append(b, c|varintContinue)
+		c = byte(size & varintValueMask)
+		size >>= 7
+	}
+
+	return append(b, c)
+}
+
+func appendOffset(b []byte, ofs uint64) []byte {
+	var buf [offsetEncodingSize]byte
+
+	pos := len(buf) - 1
+	buf[pos] = byte(ofs & varintValueMask)
+
+	for ofs >>= 7; ofs != 0; ofs >>= 7 {
+		ofs--
+		pos--
+		buf[pos] = varintContinue | byte(ofs&varintValueMask)
+	}
+
+	return append(b, buf[pos:]...)
+}
+
+func appendCompressed(b, data []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(b)
+
+	zw := zlib.NewWriter(buf)
+
+	_, err := zw.Write(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,158 @@
+// Package readonly implements a read-only view of a billy.Filesystem.
+package readonly
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/go-git/go-billy/v6"
+)
+
+const writeFlags = os.O_WRONLY | os.O_RDWR | os.O_CREATE | os.O_TRUNC | os.O_APPEND
+
+// ErrReadOnly is the error of the operations rejected by the view. It
+// matches both os.ErrPermission and billy.ErrReadOnly.
+var ErrReadOnly = fmt.Errorf("%w: %w", os.ErrPermission, billy.ErrReadOnly)
+
+type readOnly struct {
+	fs billy.Filesystem
+}
+
+// New returns a view of fs rejecting every call which would modify it with
+// ErrReadOnly, wrapped in a *fs.PathError or *os.LinkError.
+func New(fs billy.Filesystem) billy.Filesystem {
+	return &readOnly{fs: fs}
+}
+
+func pathError(op, name string) error {
+	return &fs.PathError{Op: op, Path: name, Err: ErrReadOnly}
+}
+
+func (r *readOnly) Create(filename string) (billy.File, error) {
+	return nil, pathError("open", filename)
+}
+
+func (r *readOnly) Open(filename string) (billy.File, error) {
+	return r.OpenFile(filename, os.O_RDONLY, 0)
+}
+
+func (r *readOnly) OpenFile(filename string, flag int, perm fs.FileMode) (billy.File, error) {
+	if flag&writeFlags != 0 {
+		return nil, pathError("open", filename)
+	}
+
+	f, err := r.fs.OpenFile(filename, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &file{File: f}, nil
+}
+
+func (r *readOnly) Stat(filename string) (fs.FileInfo, error) {
+	return r.fs.Stat(filename)
+}
+
+func (r *readOnly) Lstat(filename string) (fs.FileInfo, error) {
+	return r.fs.Lstat(filename)
+}
+
+func (r *readOnly) Rename(oldpath, newpath string) error {
+	return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: ErrReadOnly}
+}
+
+func (r *readOnly) Remove(filename string) error {
+	return pathError("remove", filename)
+}
+
+func (r *readOnly) Join(elem ...string) string {
+	return r.fs.Join(elem...)
+}
+
+func (r *readOnly) TempFile(dir, _ string) (billy.File, error) {
+	return nil, pathError("open", dir)
+}
+
+func (r *readOnly) ReadDir(dirname string) ([]fs.DirEntry, error) {
+	return r.fs.ReadDir(dirname)
+}
+
+func (r *readOnly) MkdirAll(filename string, _ fs.FileMode) error {
+	return pathError("mkdir", filename)
+}
+
+func (r *readOnly) Symlink(_, link string) error {
+	return pathError("symlink", link)
+}
+
+func (r *readOnly) Readlink(link string) (string, error) {
+	return r.fs.Readlink(link)
+}
+
+func (r *readOnly) Chroot(p string) (billy.Filesystem, error) {
+	fs, err := r.fs.Chroot(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(fs), nil
+}
+
+func (r *readOnly) Root() string {
+	return r.fs.Root()
+}
+
+func (r *readOnly) Capabilities() billy.Capability {
+	return billy.Capabilities(r.fs) &^
+		(billy.WriteCapability | billy.ReadAndWriteCapability | billy.TruncateCapability)
+}
+
+func (r *readOnly) Chmod(name string, _ fs.FileMode) error {
+	return pathError("chmod", name)
+}
+
+func (r *readOnly) Lchown(name string, _, _ int) error {
+	return pathError("lchown", name)
+}
+
+func (r *readOnly) Chown(name string, _, _ int) error {
+	return pathError("chown", name)
+}
+
+func (r *readOnly) Chtimes(name string, _, _ time.Time) error {
+	return pathError("chtimes", name)
+}
+
+type file struct {
+	billy.File
+}
+
+func (f *file) Write([]byte) (int, error) {
+	return 0, pathError("write", f.Name())
+}
+
+func (f *file) WriteAt([]byte, int64) (int, error) {
+	return 0, pathError("write", f.Name())
+}
+
+func (f *file) Truncate(int64) error {
+	return pathError("truncate", f.Name())
+}
+
+func (f *file) Lock() error {
+	if l, ok := f.File.(billy.Locker); ok {
+		return l.Lock()
+	}
+
+	return nil
+}
+
+func (f *file) Unlock() error {
+	if l, ok := f.File.(billy.Locker); ok {
+		return l.Unlock()
+	}
+
+	return nil
+}
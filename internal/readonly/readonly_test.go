@@ -0,0 +1,94 @@
+package readonly_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/memfs"
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git-fixtures/v6/internal/readonly"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFS(t *testing.T) (billy.Filesystem, billy.Filesystem) {
+	t.Helper()
+
+	base := memfs.New()
+	require.NoError(t, util.WriteFile(base, "dir/file", []byte("content"), 0o644))
+	require.NoError(t, base.Symlink("dir/file", "link"))
+
+	return base, readonly.New(base)
+}
+
+func TestReads(t *testing.T) {
+	t.Parallel()
+
+	_, fs := newFS(t)
+
+	content, err := util.ReadFile(fs, "link")
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+
+	entries, err := fs.ReadDir("dir")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	target, err := fs.Readlink("link")
+	require.NoError(t, err)
+	assert.Equal(t, "dir/file", target)
+
+	sub, err := fs.Chroot("dir")
+	require.NoError(t, err)
+
+	content, err = util.ReadFile(sub, "file")
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+
+	assert.False(t, billy.CapabilityCheck(fs, billy.WriteCapability))
+	assert.True(t, billy.CapabilityCheck(fs, billy.ReadCapability))
+}
+
+func TestWritesRejected(t *testing.T) {
+	t.Parallel()
+
+	base, fs := newFS(t)
+	sub, err := fs.Chroot("dir")
+	require.NoError(t, err)
+
+	f, err := fs.Open("dir/file")
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	calls := map[string]func() error{
+		"create": func() error { _, err := fs.Create("new"); return err },
+		"open":   func() error { _, err := fs.OpenFile("dir/file", os.O_RDWR, 0); return err },
+		"append": func() error { _, err := fs.OpenFile("dir/file", os.O_WRONLY|os.O_APPEND, 0); return err },
+		"temp":   func() error { _, err := fs.TempFile("dir", "tmp"); return err },
+		"rename": func() error { return fs.Rename("dir/file", "moved") },
+		"remove": func() error { return fs.Remove("dir/file") },
+		"mkdir":  func() error { return fs.MkdirAll("new/dir", 0o755) },
+		"link":   func() error { return fs.Symlink("dir/file", "new") },
+		"chmod":  func() error { return fs.(billy.Chmod).Chmod("dir/file", 0o600) },
+		"chroot": func() error { return util.WriteFile(sub, "new", nil, 0o644) },
+		"write":  func() error { _, err := f.Write([]byte("x")); return err },
+		"trunc":  func() error { return f.Truncate(0) },
+	}
+
+	for name, call := range calls {
+		err := call()
+		require.ErrorIs(t, err, os.ErrPermission, name)
+		require.ErrorIs(t, err, billy.ErrReadOnly, name)
+	}
+
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+
+	entries, err := base.ReadDir("")
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "base modified")
+}
@@ -0,0 +1,92 @@
+// Package revfile encodes version 1 reverse index (.rev) files.
+package revfile
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported object format")
+
+const (
+	// Magic is the signature at the start of rev files.
+	Magic = "RIDX"
+	// VersionSupported is the only rev version written and read.
+	VersionSupported uint32 = 1
+
+	hashIDSHA1   uint32 = 1
+	hashIDSHA256 uint32 = 2
+)
+
+// Entry is an object of the pack being indexed.
+type Entry struct {
+	Hash   string
+	Offset int64
+}
+
+// Encode returns a version 1 rev file for entries of the pack whose checksum
+// is packChecksum. Entries may be given in any order.
+func Encode(format string, entries []Entry, packChecksum []byte) ([]byte, error) {
+	id, err := HashID(format)
+	if err != nil {
+		return nil, err
+	}
+
+	// byHash holds the positions of the objects in idx order.
+	byHash := make([]int, len(entries))
+	for i := range byHash {
+		byHash[i] = i
+	}
+
+	slices.SortFunc(byHash, func(a, b int) int {
+		return strings.Compare(entries[a].Hash, entries[b].Hash)
+	})
+
+	// byOffset holds the idx positions of the objects in pack order.
+	byOffset := make([]uint32, len(entries))
+	for i := range byOffset {
+		byOffset[i] = uint32(i)
+	}
+
+	slices.SortFunc(byOffset, func(a, b uint32) int {
+		return cmp.Compare(entries[byHash[a]].Offset, entries[byHash[b]].Offset)
+	})
+
+	var buf bytes.Buffer
+
+	buf.WriteString(Magic)
+	_ = binary.Write(&buf, binary.BigEndian, VersionSupported)
+	_ = binary.Write(&buf, binary.BigEndian, id)
+	_ = binary.Write(&buf, binary.BigEndian, byOffset)
+
+	buf.Write(packChecksum)
+
+	h, err := object.NewHasher(format)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Write(buf.Bytes())
+	buf.Write(h.Sum(nil))
+
+	return buf.Bytes(), nil
+}
+
+// HashID returns the hash function identifier stored in rev files for format.
+func HashID(format string) (uint32, error) {
+	switch format {
+	case object.FormatSHA1:
+		return hashIDSHA1, nil
+	case object.FormatSHA256:
+		return hashIDSHA256, nil
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+}
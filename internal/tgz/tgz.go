@@ -1,3 +1,10 @@
+// Package tgz extracts the gzipped tarballs fixtures are stored in.
+//
+// Regular files, directories, symbolic links and hard links are extracted
+// with their permission bits, and optionally their modification times.
+// Ownership is not restored: the uid, gid, user and group names recorded in
+// the archive are ignored, and extracted files belong to the running user,
+// as with tar --no-same-owner.
 package tgz
 
 import (
@@ -9,15 +16,24 @@ import (
 	"io/fs"
 	"math"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-git/go-billy/v6"
 	"github.com/go-git/go-billy/v6/memfs"
+	"github.com/go-git/go-billy/v6/osfs"
 )
 
 var (
 	ErrUnableToUntarType            = errors.New("unable to untar type")
 	ErrCannotBeNegative             = errors.New("mode cannot be negative")
 	ErrCannotBeGreaterThanMaxUInt32 = errors.New("mode cannot be greater than max uint32")
+	ErrAbsolutePath                 = errors.New("absolute path in archive")
+	ErrPathTraversal                = errors.New("path traversal in archive")
+	ErrSymlinkEscape                = errors.New("path escapes through a symlink")
 )
 
 //nolint:gochecknoglobals
@@ -25,10 +41,34 @@ var MemFactory = func() (billy.Filesystem, error) {
 	return memfs.New(), nil
 }
 
+// Chtimes is the subset of billy.Change used to restore modification times.
+type Chtimes interface {
+	Chtimes(name string, atime time.Time, mtime time.Time) error
+}
+
+// Option configures Extract.
+type Option func(*options)
+
+type options struct {
+	modTime func(time.Time) time.Time
+}
+
+// WithModTime sets the modification time of every extracted file and
+// directory to the one returned by modTime, given the modification time
+// recorded in the archive. Symlinks are left untouched.
+//
+// Times are only set on filesystems implementing Chtimes, and on osfs ones.
+// Other filesystems, such as memfs, are left with the extraction time.
+func WithModTime(modTime func(time.Time) time.Time) Option {
+	return func(o *options) {
+		o.modTime = modTime
+	}
+}
+
 // Extract decompress a gziped tarball into the fs billy.Filesystem.
 //
 // A non-nil error is returned if the method fails to complete.
-func Extract(tgz billy.File, fs billy.Filesystem) (err error) {
+func Extract(tgz billy.File, fs billy.Filesystem, opts ...Option) (err error) {
 	defer func() {
 		errClose := tgz.Close()
 		if err == nil {
@@ -41,12 +81,131 @@ func Extract(tgz billy.File, fs billy.Filesystem) (err error) {
 		return
 	}
 
-	err = unTar(fs, tar)
+	return extract(tar, fs, opts)
+}
+
+// Decompress reads the gziped tarball tgz whole, returning the uncompressed
+// tarball to be extracted with ExtractTar.
+func Decompress(tgz billy.File) (data []byte, err error) {
+	defer func() {
+		errClose := tgz.Close()
+		if err == nil {
+			err = errClose
+		}
+	}()
+
+	zip, err := gzip.NewReader(tgz)
 	if err != nil {
-		return
+		return nil, err
+	}
+
+	return io.ReadAll(zip)
+}
+
+// ExtractTar extracts the uncompressed tarball r into the fs
+// billy.Filesystem.
+func ExtractTar(r io.Reader, fs billy.Filesystem, opts ...Option) error {
+	return extract(tar.NewReader(r), fs, opts)
+}
+
+// Copy copies the contents of src, typically a previous extraction, into
+// dst as if extracting an archive of src. Modification times are taken from
+// src.
+func Copy(src, dst billy.Filesystem, opts ...Option) error {
+	var times []modTime
+
+	err := copyDir(src, dst, "", &times)
+	if err != nil {
+		return err
 	}
 
-	return
+	return finish(dst, times, opts)
+}
+
+func extract(src *tar.Reader, fs billy.Filesystem, opts []Option) error {
+	times, err := unTar(fs, src)
+	if err != nil {
+		return err
+	}
+
+	return finish(fs, times, opts)
+}
+
+// finish applies the options to the entries extracted into fs.
+func finish(fs billy.Filesystem, times []modTime, opts []Option) error {
+	o := &options{modTime: nil}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.modTime != nil {
+		return restoreModTimes(fs, times, o.modTime)
+	}
+
+	return nil
+}
+
+// copyDir copies the contents of dir from src to dst, appending their
+// modification times to times in the order unTar would.
+func copyDir(src, dst billy.Filesystem, dir string, times *[]modTime) error {
+	entries, err := src.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		name := path.Join(dir, e.Name())
+
+		fi, err := src.Lstat(name)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case fi.Mode()&os.ModeSymlink != 0:
+			target, err := src.Readlink(name)
+			if err != nil {
+				return err
+			}
+
+			err = makeSymlink(dst, name, target)
+			if err != nil {
+				return err
+			}
+
+			continue
+		case fi.IsDir():
+			err = dst.MkdirAll(name, fi.Mode().Perm())
+			if err != nil {
+				return err
+			}
+
+			*times = append(*times, modTime{path: name, time: fi.ModTime()})
+
+			err = copyDir(src, dst, name, times)
+		default:
+			*times = append(*times, modTime{path: name, time: fi.ModTime()})
+
+			err = copyFile(src, dst, name, fi.Mode())
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func copyFile(src, dst billy.Filesystem, name string, mode os.FileMode) error {
+	r, err := src.Open(name)
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	return makeFile(dst, name, mode, r)
 }
 
 func zipTarReader(r io.Reader) (*tar.Reader, error) {
@@ -70,7 +229,18 @@ func filemode(mode int64) (fs.FileMode, error) {
 	return os.FileMode(mode), nil
 }
 
-func unTar(fs billy.Filesystem, src *tar.Reader) error {
+// modTime is the modification time recorded in the archive for an extracted
+// file or directory.
+type modTime struct {
+	path string
+	time time.Time
+}
+
+// unTar extracts src into fs, returning the modification times of the
+// extracted files and directories in archive order.
+func unTar(fs billy.Filesystem, src *tar.Reader) ([]modTime, error) {
+	var times []modTime
+
 	for {
 		header, err := src.Next()
 		if err != nil {
@@ -78,38 +248,139 @@ func unTar(fs billy.Filesystem, src *tar.Reader) error {
 				break
 			}
 
-			return err
+			return nil, err
 		}
 
-		dst := header.Name
+		dst, err := sanitize(fs, header.Name)
+		if err != nil {
+			return nil, err
+		}
 
 		mode, err := filemode(header.Mode)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		switch header.Typeflag {
 		case tar.TypeDir:
 			err := fs.MkdirAll(dst, mode)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		case tar.TypeReg:
 			err := makeFile(fs, dst, mode, src)
 			if err != nil {
-				return err
+				return nil, err
+			}
+		case tar.TypeSymlink:
+			err := makeSymlink(fs, dst, header.Linkname)
+			if err != nil {
+				return nil, err
+			}
+		case tar.TypeLink:
+			err := makeHardlink(fs, dst, header.Linkname)
+			if err != nil {
+				return nil, err
 			}
 		default:
-			return fmt.Errorf("%w: %c in file %s", ErrUnableToUntarType,
+			return nil, fmt.Errorf("%w: %c in file %s", ErrUnableToUntarType,
 				header.Typeflag, header.Name)
 		}
+
+		if header.Typeflag != tar.TypeSymlink {
+			times = append(times, modTime{path: dst, time: header.ModTime})
+		}
+	}
+
+	return times, nil
+}
+
+// restoreModTimes sets the modification times of the extracted entries once
+// all of them exist, in reverse archive order so that directories are set
+// after their contents.
+func restoreModTimes(fs billy.Filesystem, times []modTime, fn func(time.Time) time.Time) error {
+	set := chtimes(fs)
+	if set == nil {
+		return nil
+	}
+
+	for _, t := range slices.Backward(times) {
+		mtime := fn(t.time)
+
+		err := set(t.path, mtime, mtime)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chtimes returns the function setting file times on fs, or nil if fs does
+// not support it. osfs does not implement billy.Change, so its files are
+// changed directly under its root.
+func chtimes(fs billy.Filesystem) func(name string, atime, mtime time.Time) error {
+	switch fs := fs.(type) {
+	case Chtimes:
+		return fs.Chtimes
+	case *osfs.BoundOS:
+		return func(name string, atime, mtime time.Time) error {
+			return os.Chtimes(filepath.Join(fs.Root(), filepath.FromSlash(name)), atime, mtime)
+		}
 	}
 
 	return nil
 }
 
+// sanitize validates the archive entry name and returns it cleaned. Absolute
+// paths and ".." components are rejected, as are paths going through a
+// symlink already present in fs, which could otherwise be used to write
+// outside of it.
+func sanitize(fs billy.Filesystem, name string) (string, error) {
+	if path.IsAbs(name) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("%w: %s", ErrAbsolutePath, name)
+	}
+
+	for _, part := range strings.FieldsFunc(name, isSeparator) {
+		if part == ".." {
+			return "", fmt.Errorf("%w: %s", ErrPathTraversal, name)
+		}
+	}
+
+	clean := path.Clean(filepath.ToSlash(name))
+
+	sfs, ok := fs.(billy.Symlink)
+	if !ok || clean == "." {
+		return clean, nil
+	}
+
+	parts := strings.Split(clean, "/")
+	for i := range parts {
+		fi, err := sfs.Lstat(path.Join(parts[:i+1]...))
+		if errors.Is(err, os.ErrNotExist) {
+			break
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("%w: %s", ErrSymlinkEscape, name)
+		}
+	}
+
+	return clean, nil
+}
+
+// isSeparator reports whether r separates path components. Backslashes are
+// included so that archives cannot traverse out of the root on Windows.
+func isSeparator(r rune) bool {
+	return r == '/' || r == '\\'
+}
+
 func makeFile(fs billy.Filesystem, path string, mode os.FileMode, contents io.Reader) (err error) {
-	w, err := fs.Create(path)
+	w, err := fs.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
 	if err != nil {
 		return err
 	}
@@ -126,8 +397,10 @@ func makeFile(fs billy.Filesystem, path string, mode os.FileMode, contents io.Re
 		return err
 	}
 
-	if fs, ok := fs.(billy.Change); ok {
-		err = fs.Chmod(path, mode)
+	// The mode given on creation may be masked, e.g. by the process umask,
+	// so set it explicitly to preserve the executable bit.
+	if fs, ok := fs.(billy.Chmod); ok {
+		err = fs.Chmod(path, mode.Perm())
 		if err != nil {
 			return err
 		}
@@ -135,3 +408,37 @@ func makeFile(fs billy.Filesystem, path string, mode os.FileMode, contents io.Re
 
 	return nil
 }
+
+func makeSymlink(fs billy.Filesystem, link, target string) error {
+	sfs, ok := fs.(billy.Symlink)
+	if !ok {
+		return fmt.Errorf("%w: %c in file %s: filesystem does not support symlinks",
+			ErrUnableToUntarType, tar.TypeSymlink, link)
+	}
+
+	return sfs.Symlink(target, link)
+}
+
+// makeHardlink creates link as a copy of the previously extracted target, as
+// billy has no support for hard links. The target is validated like any other
+// entry name, so that it cannot be used to read files outside of fs.
+func makeHardlink(fs billy.Filesystem, link, target string) error {
+	src, err := sanitize(fs, target)
+	if err != nil {
+		return err
+	}
+
+	fi, err := fs.Stat(src)
+	if err != nil {
+		return fmt.Errorf("hardlink %s: %w", link, err)
+	}
+
+	r, err := fs.Open(src)
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	return makeFile(fs, link, fi.Mode(), r)
+}
@@ -1,14 +1,21 @@
 package tgz_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/memfs"
 	"github.com/go-git/go-billy/v6/osfs"
+	"github.com/go-git/go-billy/v6/util"
 	"github.com/go-git/go-git-fixtures/v6/internal/tgz"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -129,3 +136,323 @@ func TestExtract(t *testing.T) { //nolint
 		}
 	}
 }
+
+type entry struct {
+	name     string
+	typeflag byte
+	linkname string
+	body     string
+	mode     int64
+	modTime  time.Time
+}
+
+// archive returns a billy.File holding a gzipped tarball of entries.
+func archive(t *testing.T, entries ...entry) billy.File {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+
+	for _, e := range entries {
+		mode := e.mode
+		switch {
+		case mode != 0:
+		case e.typeflag == tar.TypeDir:
+			mode = 0o755
+		default:
+			mode = 0o644
+		}
+
+		err := tw.WriteHeader(&tar.Header{
+			Typeflag: e.typeflag,
+			Name:     e.name,
+			Linkname: e.linkname,
+			Mode:     mode,
+			Size:     int64(len(e.body)),
+			ModTime:  e.modTime,
+		})
+		require.NoError(t, err)
+
+		_, err = tw.Write([]byte(e.body))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, zw.Close())
+
+	src := memfs.New()
+	require.NoError(t, util.WriteFile(src, "archive.tgz", buf.Bytes(), 0o644))
+
+	f, err := src.Open("archive.tgz")
+	require.NoError(t, err)
+
+	return f
+}
+
+func TestExtractRejectsUnsafePaths(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		entries []entry
+		wantErr error
+	}{
+		{
+			name:    "absolute",
+			entries: []entry{{name: "/etc/passwd", typeflag: tar.TypeReg, body: "x"}},
+			wantErr: tgz.ErrAbsolutePath,
+		},
+		{
+			name:    "parent",
+			entries: []entry{{name: "../escape", typeflag: tar.TypeReg, body: "x"}},
+			wantErr: tgz.ErrPathTraversal,
+		},
+		{
+			name:    "nested parent",
+			entries: []entry{{name: "a/../../escape", typeflag: tar.TypeReg, body: "x"}},
+			wantErr: tgz.ErrPathTraversal,
+		},
+		{
+			name:    "parent staying inside",
+			entries: []entry{{name: "a/../b", typeflag: tar.TypeReg, body: "x"}},
+			wantErr: tgz.ErrPathTraversal,
+		},
+		{
+			name:    "backslash parent",
+			entries: []entry{{name: `a\..\..\escape`, typeflag: tar.TypeReg, body: "x"}},
+			wantErr: tgz.ErrPathTraversal,
+		},
+		{
+			name:    "parent directory",
+			entries: []entry{{name: "../dir/", typeflag: tar.TypeDir}},
+			wantErr: tgz.ErrPathTraversal,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := memfs.New()
+			err := tgz.Extract(archive(t, tc.entries...), fs)
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestExtractRejectsWritesThroughSymlinks(t *testing.T) {
+	t.Parallel()
+
+	outside := t.TempDir()
+	fs := osfs.New(t.TempDir())
+	require.NoError(t, fs.Symlink(outside, "link"))
+
+	err := tgz.Extract(archive(t, entry{name: "link/file", typeflag: tar.TypeReg, body: "x"}), fs)
+	require.ErrorIs(t, err, tgz.ErrSymlinkEscape)
+
+	err = tgz.Extract(archive(t, entry{name: "link", typeflag: tar.TypeReg, body: "x"}), fs)
+	require.ErrorIs(t, err, tgz.ErrSymlinkEscape)
+
+	entries, err := os.ReadDir(outside)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestExtractDotPrefix(t *testing.T) {
+	t.Parallel()
+
+	fs := memfs.New()
+	err := tgz.Extract(archive(t,
+		entry{name: "./", typeflag: tar.TypeDir},
+		entry{name: "./dir/", typeflag: tar.TypeDir},
+		entry{name: "./dir/file", typeflag: tar.TypeReg, body: "x"},
+	), fs)
+	require.NoError(t, err)
+
+	content, err := util.ReadFile(fs, "dir/file")
+	require.NoError(t, err)
+	assert.Equal(t, "x", string(content))
+}
+
+func TestExtractLinksAndModes(t *testing.T) {
+	t.Parallel()
+
+	factories := []struct {
+		name    string
+		factory func() (billy.Filesystem, error)
+	}{
+		{name: "mem", factory: tgz.MemFactory},
+		{name: "osfs-temp", factory: func() (billy.Filesystem, error) {
+			return osfs.New(t.TempDir(), osfs.WithBoundOS()), nil
+		}},
+	}
+
+	for _, ff := range factories {
+		t.Run(ff.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs, err := ff.factory()
+			require.NoError(t, err)
+
+			err = tgz.Extract(archive(t,
+				entry{name: "dir/", typeflag: tar.TypeDir},
+				entry{name: "dir/file", typeflag: tar.TypeReg, body: "content"},
+				entry{name: "run.sh", typeflag: tar.TypeReg, body: "#!/bin/sh\n", mode: 0o755},
+				entry{name: "link", typeflag: tar.TypeSymlink, linkname: "dir/file"},
+				entry{name: "broken", typeflag: tar.TypeSymlink, linkname: "missing"},
+				entry{name: "hard", typeflag: tar.TypeLink, linkname: "dir/file"},
+			), fs)
+			require.NoError(t, err)
+
+			sfs, ok := fs.(billy.Symlink)
+			require.True(t, ok)
+
+			target, err := sfs.Readlink("link")
+			require.NoError(t, err)
+			assert.Equal(t, "dir/file", target)
+
+			target, err = sfs.Readlink("broken")
+			require.NoError(t, err)
+			assert.Equal(t, "missing", target)
+
+			content, err := util.ReadFile(fs, "hard")
+			require.NoError(t, err)
+			assert.Equal(t, "content", string(content))
+
+			fi, err := fs.Stat("run.sh")
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
+
+			fi, err = fs.Stat("dir/file")
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o644), fi.Mode().Perm())
+		})
+	}
+}
+
+func TestExtractRejectsUnsafeHardlinks(t *testing.T) {
+	t.Parallel()
+
+	fs := memfs.New()
+	err := tgz.Extract(archive(t,
+		entry{name: "hard", typeflag: tar.TypeLink, linkname: "../../etc/passwd"},
+	), fs)
+	require.ErrorIs(t, err, tgz.ErrPathTraversal)
+
+	fs = memfs.New()
+	err = tgz.Extract(archive(t,
+		entry{name: "link", typeflag: tar.TypeSymlink, linkname: "/etc"},
+		entry{name: "hard", typeflag: tar.TypeLink, linkname: "link/passwd"},
+	), fs)
+	require.ErrorIs(t, err, tgz.ErrSymlinkEscape)
+}
+
+func TestExtractWithModTime(t *testing.T) {
+	t.Parallel()
+
+	archived := time.Date(2015, 4, 5, 21, 30, 47, 0, time.UTC)
+	fixed := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		modTime func(time.Time) time.Time
+		want    time.Time
+	}{
+		{name: "archived", modTime: func(t time.Time) time.Time { return t }, want: archived},
+		{name: "fixed", modTime: func(time.Time) time.Time { return fixed }, want: fixed},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := osfs.New(t.TempDir(), osfs.WithBoundOS())
+			err := tgz.Extract(archive(t,
+				entry{name: "dir/", typeflag: tar.TypeDir, modTime: archived},
+				entry{name: "dir/file", typeflag: tar.TypeReg, body: "content", modTime: archived},
+				entry{name: "hard", typeflag: tar.TypeLink, linkname: "dir/file", modTime: archived},
+				entry{name: "link", typeflag: tar.TypeSymlink, linkname: "dir/file", modTime: archived},
+			), fs, tgz.WithModTime(tc.modTime))
+			require.NoError(t, err)
+
+			for _, name := range []string{"dir", "dir/file", "hard"} {
+				fi, err := fs.Stat(name)
+				require.NoError(t, err)
+				assert.True(t, tc.want.Equal(fi.ModTime()), "%s: %v", name, fi.ModTime())
+			}
+		})
+	}
+}
+
+func TestExtractWithoutModTime(t *testing.T) {
+	t.Parallel()
+
+	archived := time.Date(2015, 4, 5, 21, 30, 47, 0, time.UTC)
+
+	fs := osfs.New(t.TempDir(), osfs.WithBoundOS())
+	err := tgz.Extract(archive(t,
+		entry{name: "file", typeflag: tar.TypeReg, body: "content", modTime: archived},
+	), fs)
+	require.NoError(t, err)
+
+	fi, err := fs.Stat("file")
+	require.NoError(t, err)
+	assert.True(t, fi.ModTime().After(archived))
+}
+
+func TestDecompressAndExtractTar(t *testing.T) {
+	t.Parallel()
+
+	data, err := tgz.Decompress(archive(t,
+		entry{name: "dir/", typeflag: tar.TypeDir},
+		entry{name: "dir/file", typeflag: tar.TypeReg, body: "content"},
+	))
+	require.NoError(t, err)
+
+	for range 2 {
+		fs := memfs.New()
+		require.NoError(t, tgz.ExtractTar(bytes.NewReader(data), fs))
+
+		content, err := util.ReadFile(fs, "dir/file")
+		require.NoError(t, err)
+		assert.Equal(t, "content", string(content))
+	}
+}
+
+func TestCopy(t *testing.T) {
+	t.Parallel()
+
+	archived := time.Date(2015, 4, 5, 21, 30, 47, 0, time.UTC)
+
+	src := osfs.New(t.TempDir(), osfs.WithBoundOS())
+	err := tgz.Extract(archive(t,
+		entry{name: "dir/", typeflag: tar.TypeDir, modTime: archived},
+		entry{name: "dir/file", typeflag: tar.TypeReg, body: "content", modTime: archived},
+		entry{name: "run.sh", typeflag: tar.TypeReg, body: "#!/bin/sh\n", mode: 0o755, modTime: archived},
+		entry{name: "link", typeflag: tar.TypeSymlink, linkname: "dir/file"},
+	), src, tgz.WithModTime(func(t time.Time) time.Time { return t }))
+	require.NoError(t, err)
+
+	dst := osfs.New(t.TempDir(), osfs.WithBoundOS())
+	err = tgz.Copy(src, dst, tgz.WithModTime(func(t time.Time) time.Time { return t }))
+	require.NoError(t, err)
+
+	content, err := util.ReadFile(dst, "dir/file")
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+
+	fi, err := dst.Stat("run.sh")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
+	assert.True(t, archived.Equal(fi.ModTime()))
+
+	fi, err = dst.Stat("dir")
+	require.NoError(t, err)
+	assert.True(t, archived.Equal(fi.ModTime()))
+
+	target, err := dst.Readlink("link")
+	require.NoError(t, err)
+	assert.Equal(t, "dir/file", target)
+}
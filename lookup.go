@@ -0,0 +1,107 @@
+package fixtures
+
+import (
+	"fmt"
+	"sync"
+)
+
+// fixtureIndex maps a hash to the indices of the fixtures holding it.
+type fixtureIndex map[string][]int
+
+func newFixtureIndex(key func(*Fixture) string) func() fixtureIndex {
+	return sync.OnceValue(func() fixtureIndex {
+		idx := fixtureIndex{}
+
+		for i, f := range fixtures {
+			if k := key(f); k != "" {
+				idx[k] = append(idx[k], i)
+			}
+		}
+
+		return idx
+	})
+}
+
+//nolint:gochecknoglobals
+var (
+	headIndex         = newFixtureIndex(func(f *Fixture) string { return f.Head })
+	packfileHashIndex = newFixtureIndex(func(f *Fixture) string { return f.PackfileHash })
+	dotGitHashIndex   = newFixtureIndex(func(f *Fixture) string { return f.DotGitHash })
+	worktreeHashIndex = newFixtureIndex(func(f *Fixture) string { return f.WorktreeHash })
+)
+
+func (idx fixtureIndex) lookup(key string) Fixtures {
+	r := make(Fixtures, 0, len(idx[key]))
+	for _, i := range idx[key] {
+		r = append(r, fixtures[i].Clone())
+	}
+
+	return r
+}
+
+// ByHead returns the fixtures whose HEAD is the given commit.
+func ByHead(hash string) Fixtures {
+	return headIndex().lookup(hash)
+}
+
+// ByPackfileHash returns the fixtures with the given packfile.
+func ByPackfileHash(hash string) Fixtures {
+	return packfileHashIndex().lookup(hash)
+}
+
+// ByDotGitHash returns the fixtures with the given .git directory archive.
+func ByDotGitHash(hash string) Fixtures {
+	return dotGitHashIndex().lookup(hash)
+}
+
+// ByWorktreeHash returns the fixtures with the given worktree archive.
+func ByWorktreeHash(hash string) Fixtures {
+	return worktreeHashIndex().lookup(hash)
+}
+
+func (g Fixtures) ByHead(hash string) Fixtures {
+	return g.filter(func(f *Fixture) bool { return f.Head == hash })
+}
+
+func (g Fixtures) ByPackfileHash(hash string) Fixtures {
+	return g.filter(func(f *Fixture) bool { return f.PackfileHash == hash })
+}
+
+func (g Fixtures) ByDotGitHash(hash string) Fixtures {
+	return g.filter(func(f *Fixture) bool { return f.DotGitHash == hash })
+}
+
+func (g Fixtures) ByWorktreeHash(hash string) Fixtures {
+	return g.filter(func(f *Fixture) bool { return f.WorktreeHash == hash })
+}
+
+func (g Fixtures) filter(match func(*Fixture) bool) Fixtures {
+	r := make(Fixtures, 0, len(g))
+	for _, f := range g {
+		if match(f) {
+			r = append(r, f.Clone())
+		}
+	}
+
+	return r
+}
+
+// Dependencies returns the fixtures this one requires to be usable, e.g. the
+// fixture holding the base objects of a thin pack. Returns an empty set for
+// self-contained fixtures.
+//
+// It panics if a dependency is not a known fixture, which is a mistake in
+// the fixture catalogue.
+func (f *Fixture) Dependencies() Fixtures {
+	deps := make(Fixtures, 0, len(f.dependencies))
+	for _, name := range f.dependencies {
+		dep := ByName(name)
+		if dep == nil {
+			panic(fmt.Sprintf("fixture %s depends on unknown fixture %q", f.runName(), name))
+		}
+
+		deps = append(deps, dep)
+	}
+
+	return deps
+}
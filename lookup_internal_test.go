@@ -0,0 +1,35 @@
+package fixtures
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDependenciesExist checks that the dependencies of every fixture name an
+// existing fixture.
+func TestDependenciesExist(t *testing.T) {
+	t.Parallel()
+
+	declared := 0
+
+	for _, f := range fixtures {
+		for _, name := range f.dependencies {
+			assert.NotNil(t, ByName(name), "dependency %q of %s", name, f.Name)
+
+			declared++
+		}
+
+		assert.NotPanics(t, func() { f.Dependencies() }, f.Name)
+	}
+
+	assert.NotZero(t, declared)
+}
+
+func TestDependenciesUnknown(t *testing.T) {
+	t.Parallel()
+
+	f := &Fixture{Name: "broken", dependencies: []string{"spinaker"}}
+
+	assert.PanicsWithValue(t, `fixture broken depends on unknown fixture "spinaker"`, func() { f.Dependencies() })
+}
@@ -0,0 +1,104 @@
+package fixtures_test
+
+import (
+	"testing"
+
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByHashes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		lookup func(string) fixtures.Fixtures
+		method func(fixtures.Fixtures, string) fixtures.Fixtures
+		hash   func(*fixtures.Fixture) string
+	}{
+		{
+			name:   "head",
+			lookup: fixtures.ByHead,
+			method: fixtures.Fixtures.ByHead,
+			hash:   func(f *fixtures.Fixture) string { return f.Head },
+		},
+		{
+			name:   "packfile",
+			lookup: fixtures.ByPackfileHash,
+			method: fixtures.Fixtures.ByPackfileHash,
+			hash:   func(f *fixtures.Fixture) string { return f.PackfileHash },
+		},
+		{
+			name:   "dotgit",
+			lookup: fixtures.ByDotGitHash,
+			method: fixtures.Fixtures.ByDotGitHash,
+			hash:   func(f *fixtures.Fixture) string { return f.DotGitHash },
+		},
+		{
+			name:   "worktree",
+			lookup: fixtures.ByWorktreeHash,
+			method: fixtures.Fixtures.ByWorktreeHash,
+			hash:   func(f *fixtures.Fixture) string { return f.WorktreeHash },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, f := range fixtures.All() {
+				hash := tc.hash(f)
+				if hash == "" {
+					continue
+				}
+
+				got := tc.lookup(hash)
+				assert.Contains(t, got, f)
+				assert.Equal(t, tc.method(fixtures.All(), hash), got)
+
+				for _, g := range got {
+					assert.Equal(t, hash, tc.hash(g))
+				}
+			}
+
+			assert.Empty(t, tc.lookup(""))
+			assert.Empty(t, tc.lookup("0000000000000000000000000000000000000000"))
+		})
+	}
+}
+
+func TestByHashesShared(t *testing.T) {
+	t.Parallel()
+
+	got := fixtures.ByPackfileHash("769137af7784db501bca677fbd56fef8b52515b7")
+	require.Len(t, got, 2)
+	assert.Equal(t, "commit-graph", got[0].Name)
+	assert.Equal(t, "commit-graph-chain", got[1].Name)
+
+	got = fixtures.ByHead("6ecf0ef2c2dffb796033e5a02219af86ec6584e5")
+	assert.Greater(t, len(got), 1)
+
+	got = fixtures.Basic().ByHead("6ecf0ef2c2dffb796033e5a02219af86ec6584e5").ByPackfileHash(
+		"a3fed42da1e8189a077c0e6846c040dcf73fc9dd")
+	require.Len(t, got, 1)
+	assert.Equal(t, "basic-ofs-delta", got[0].Name)
+}
+
+func TestDependencies(t *testing.T) {
+	t.Parallel()
+
+	thinpack := fixtures.ByName("thinpack")
+	require.NotNil(t, thinpack)
+
+	deps := thinpack.Dependencies()
+	require.Len(t, deps, 1)
+	assert.Equal(t, "spinnaker", deps[0].Name)
+
+	// The thin pack adds a commit on top of the HEAD of its base.
+	commits := deps[0].Commits()
+	_, ok := commits.Get(deps[0].Head)
+	assert.True(t, ok)
+
+	assert.Empty(t, fixtures.ByName("spinnaker").Dependencies())
+}
@@ -0,0 +1,101 @@
+package fixtures
+
+import (
+	"slices"
+	"strings"
+)
+
+// MultiPackIndex is the expected content of the objects/pack/multi-pack-index
+// file of a fixture's .git directory. Fields use plain types so
+// go-git-fixtures does not depend on go-git.
+type MultiPackIndex struct {
+	// Version is the version of the file format.
+	Version int
+	// ObjectFormat is the hash algorithm of the object ids, sha1 or sha256.
+	ObjectFormat string
+	// Checksum is the hex-encoded trailing checksum of the file.
+	Checksum string
+	// Packs are the names of the idx files of the indexed packs, in the
+	// order of the PNAM chunk.
+	Packs []string
+	// PreferredPack is the position in Packs of the preferred pack, whose
+	// objects come first in the pseudo-pack order. It is -1 if the file has
+	// no RIDX chunk to tell it.
+	PreferredPack int
+	// Chunks is the chunk table, in file order.
+	Chunks []MIDXChunk
+	// Objects are the indexed objects, ordered by hash. For objects stored
+	// in several packs, the pack selected by git is recorded.
+	Objects []MIDXObject
+	// ReverseIndex is the content of the RIDX chunk: the position in Objects
+	// of each object, in pseudo-pack order. Nil if there is no RIDX chunk.
+	ReverseIndex []uint32
+	// Bitmap is the name of the multi-pack bitmap file in objects/pack.
+	// Empty if there is none.
+	Bitmap string
+}
+
+// MIDXChunk is an entry of the chunk table of a multi-pack-index.
+type MIDXChunk struct {
+	// ID is the four letter chunk id, e.g. OIDF.
+	ID string
+	// Offset is the position of the chunk from the start of the file.
+	Offset int64
+	// Size is the length of the chunk in bytes.
+	Size int64
+}
+
+// MIDXObject is an object of a multi-pack-index.
+type MIDXObject struct {
+	// Hash is the hex-encoded object id.
+	Hash string
+	// Pack is the position in MultiPackIndex.Packs of the pack storing it.
+	Pack int
+	// Offset is the position of the object in the pack.
+	Offset int64
+}
+
+// MultiPackIndex returns the expected content of the fixture's
+// multi-pack-index, decoded from the archives when generating the package.
+// Returns nil if the fixture's .git directory has none, see
+// TagMultiPackIndex.
+func (f *Fixture) MultiPackIndex() *MultiPackIndex {
+	if f.DotGitHash == "" {
+		return nil
+	}
+
+	m, ok := fixtureMultiPackIndexes["git-"+f.DotGitHash]
+	if !ok {
+		return nil
+	}
+
+	c := *m
+	c.Packs = slices.Clone(m.Packs)
+	c.Chunks = slices.Clone(m.Chunks)
+	c.Objects = slices.Clone(m.Objects)
+	c.ReverseIndex = slices.Clone(m.ReverseIndex)
+
+	return &c
+}
+
+// Object returns the entry of the object hash.
+func (m *MultiPackIndex) Object(hash string) (MIDXObject, bool) {
+	i, ok := slices.BinarySearchFunc(m.Objects, hash, func(o MIDXObject, h string) int {
+		return strings.Compare(o.Hash, h)
+	})
+	if !ok {
+		return MIDXObject{}, false
+	}
+
+	return m.Objects[i], true
+}
+
+// Chunk returns the entry of the chunk table with the given id.
+func (m *MultiPackIndex) Chunk(id string) (MIDXChunk, bool) {
+	i := slices.IndexFunc(m.Chunks, func(c MIDXChunk) bool { return c.ID == id })
+	if i < 0 {
+		return MIDXChunk{}, false
+	}
+
+	return m.Chunks[i], true
+}
@@ -0,0 +1,157 @@
+// Code generated by internal/cmd/genmeta. DO NOT EDIT.
+
+package fixtures
+
+//nolint:gochecknoglobals
+var fixtureMultiPackIndexes = map[string]*MultiPackIndex{
+	"git-7cdeabb9642835c4f003aa38e6cfbe8c316d2e89": {
+		Version:      1,
+		ObjectFormat: "sha1",
+		Checksum:     "fd2ff655b96b59281b8183632f60292c7f228dc9",
+		Packs: []string{
+			"pack-45bb947ec55988747d7d928bc951bb9bd031f680.idx",
+			"pack-6df8c626bb1d4673ae04622ee1d8bc09a43cad75.idx",
+			"pack-efc23b6018e314708801df376084e1d78c985ba7.idx",
+		},
+		PreferredPack: 2,
+		Chunks: []MIDXChunk{
+			{ID: "PNAM", Offset: 84, Size: 152},
+			{ID: "OIDF", Offset: 236, Size: 1024},
+			{ID: "OIDL", Offset: 1260, Size: 340},
+			{ID: "OOFF", Offset: 1600, Size: 136},
+			{ID: "RIDX", Offset: 1736, Size: 68},
+		},
+		Objects: []MIDXObject{
+			{Hash: "0357b3af34b76ef6d567aa3d593a3f58a17d4121", Pack: 0, Offset: 12},
+			{Hash: "0b919d88a591bd39ee0b8e37efc92e5ab949dc31", Pack: 0, Offset: 780},
+			{Hash: "26af8798dbbd98265b2bb23c81b4f4ea46f065c4", Pack: 1, Offset: 318},
+			{Hash: "38dd16da61accb1a8de6ac8709d2e65ef4a51a4a", Pack: 0, Offset: 639},
+			{Hash: "4595b9e6c1266f35b781e740456a348290b26f1d", Pack: 2, Offset: 12},
+			{Hash: "5b9300656c8574c4d56293a3a127fe163007fd92", Pack: 2, Offset: 502},
+			{Hash: "9fa731524a7f65225df3b902b2bceaadea34f8dc", Pack: 2, Offset: 937},
+			{Hash: "aa5e3f802c6a6d3eb7eac845d2293dec38ccfff1", Pack: 0, Offset: 300},
+			{Hash: "ada33f6dd6675a666e762be13d3033305c8150f2", Pack: 2, Offset: 558},
+			{Hash: "af0209608bb116e47c1e2e7cb67b5b88f30e2fdd", Pack: 2, Offset: 318},
+			{Hash: "b73772b60534b66d2fc652345007876f88c9f2e6", Pack: 0, Offset: 284},
+			{Hash: "b9840c7383d22d5f9287a1e3f9e12c09a2a66489", Pack: 0, Offset: 826},
+			{Hash: "cdba493d5a0ad9cda7ff6f7ab0631e15e8cd0224", Pack: 0, Offset: 164},
+			{Hash: "ced19c0aa48fe73cf0c60b0b17d028b7718df545", Pack: 2, Offset: 396},
+			{Hash: "dbe358fc2a7f20e5f5f9719c91910bb3746f9a45", Pack: 0, Offset: 674},
+			{Hash: "e4bd5b0d0628ce07636454015cf4c7c8fe3eda56", Pack: 2, Offset: 163},
+			{Hash: "e5ca80afc4eb3d1fb891896cb163c57d1a16d2aa", Pack: 2, Offset: 579},
+		},
+		ReverseIndex: []uint32{4, 15, 9, 13, 5, 8, 16, 6, 0, 12, 10, 7, 3, 14, 1, 11, 2},
+		Bitmap:       "multi-pack-index-fd2ff655b96b59281b8183632f60292c7f228dc9.bitmap",
+	},
+	"git-827d08501b81099c1db24f4b309b90df149ab0a2": {
+		Version:      1,
+		ObjectFormat: "sha256",
+		Checksum:     "e82624c626d91b35fae0a08d9efdd3e7b6697a62f01f4a22bab320ea3d694c1d",
+		Packs: []string{
+			"pack-0142a68c8fc758f812add2ecced08c51c1f0e09f5ef61e025b1f9621b9f1e60c.idx",
+			"pack-2edffc7b1efd9f01dbcf163c17fcc41e8762879ce452fc01c0328ae6ffd0218e.idx",
+			"pack-53c1e83f7b89c5d1185299f5359357fb65a6f282d31cec6c852a27ad62363c7f.idx",
+		},
+		PreferredPack: -1,
+		Chunks: []MIDXChunk{
+			{ID: "PNAM", Offset: 72, Size: 224},
+			{ID: "OIDF", Offset: 296, Size: 1024},
+			{ID: "OIDL", Offset: 1320, Size: 544},
+			{ID: "OOFF", Offset: 1864, Size: 136},
+		},
+		Objects: []MIDXObject{
+			{Hash: "0c9ec24e09b7575940a0da37210bcdc64f9dac0a757e88e67a43875eea7acf07", Pack: 1, Offset: 518},
+			{Hash: "2e99bb3d41e18f7376afea56c5ab1029b3bd72ac68b2205066813cb6d5bb3739", Pack: 1, Offset: 12},
+			{Hash: "437dd9d653be2b8864fc1fec221e1b6ecd6cc5f2941afd8a21641aa6366d01bc", Pack: 0, Offset: 347},
+			{Hash: "5788eeb4f2d241c12237761a322fb436ae3ade5ed24bd94e6ec51005404104ba", Pack: 1, Offset: 765},
+			{Hash: "77bc89304aeb704d3e0bbfa832bf75ee3ff11f86aaabfd4b1440e5e5656c58d9", Pack: 0, Offset: 865},
+			{Hash: "7a56d35e2a1079acdd63fd1a643a4f0a449b657e5518c7f7cd20cf611b7b05b7", Pack: 0, Offset: 721},
+			{Hash: "b26d2015a5ba62d260ff292fdb56f254aa4b4231b72761fabc2be3dbe526e576", Pack: 1, Offset: 1144},
+			{Hash: "b9c7b2dac9906a5a44e1fd5cef6f525e504c69d267d70e70b81e64757f68255b", Pack: 1, Offset: 374},
+			{Hash: "c486022bf1bbc08c7685e9e558e75d0e2a17a84265bda005ba02eae554135636", Pack: 0, Offset: 331},
+			{Hash: "cc0fb4a54bc347ee8ce7ce52f14536fb5c677ae3af038b062bb98be4d8a33e70", Pack: 1, Offset: 192},
+			{Hash: "dabe8dc2e1b5e999839da66aa5cadf24d6252e30ebb241c225cf1d60cfca20cf", Pack: 0, Offset: 12},
+			{Hash: "dd97185c21068635ab1969698a60cb03568c30dda93cb85f2cd5e4d9bfbd0fa1", Pack: 0, Offset: 923},
+			{Hash: "e75c63ee7b6df93fc77d0d5baf66bbeea190577185341e81bc60e105626b3150", Pack: 2, Offset: 374},
+			{Hash: "e9d968f79f3c2290e42b83d595e52dc3190e6ea586710d21104a09f5ff0c4733", Pack: 0, Offset: 195},
+			{Hash: "f704bf950404badb02a8ab7bbe019b0321b91702f332f44c2dad18db03fbcd8b", Pack: 1, Offset: 621},
+			{Hash: "f97e35e935590d534e012cb2db06d2bf67eee00650b8b4c269a7ba3cf16351a0", Pack: 1, Offset: 786},
+			{Hash: "fa70251daf2d85ba44361c74759097da31e1d45cad0b42409efa587d8b1960a3", Pack: 0, Offset: 686},
+		},
+	},
+	"git-9796b2f5d699a996e418ce3d43e14f819ce39133": {
+		Version:      1,
+		ObjectFormat: "sha1",
+		Checksum:     "a193f6e4ce8afda4778e8338c09166d4c8da4572",
+		Packs: []string{
+			"pack-45bb947ec55988747d7d928bc951bb9bd031f680.idx",
+			"pack-6df8c626bb1d4673ae04622ee1d8bc09a43cad75.idx",
+			"pack-efc23b6018e314708801df376084e1d78c985ba7.idx",
+		},
+		PreferredPack: -1,
+		Chunks: []MIDXChunk{
+			{ID: "PNAM", Offset: 72, Size: 152},
+			{ID: "OIDF", Offset: 224, Size: 1024},
+			{ID: "OIDL", Offset: 1248, Size: 340},
+			{ID: "OOFF", Offset: 1588, Size: 136},
+		},
+		Objects: []MIDXObject{
+			{Hash: "0357b3af34b76ef6d567aa3d593a3f58a17d4121", Pack: 0, Offset: 12},
+			{Hash: "0b919d88a591bd39ee0b8e37efc92e5ab949dc31", Pack: 0, Offset: 780},
+			{Hash: "26af8798dbbd98265b2bb23c81b4f4ea46f065c4", Pack: 1, Offset: 318},
+			{Hash: "38dd16da61accb1a8de6ac8709d2e65ef4a51a4a", Pack: 0, Offset: 639},
+			{Hash: "4595b9e6c1266f35b781e740456a348290b26f1d", Pack: 2, Offset: 12},
+			{Hash: "5b9300656c8574c4d56293a3a127fe163007fd92", Pack: 2, Offset: 502},
+			{Hash: "9fa731524a7f65225df3b902b2bceaadea34f8dc", Pack: 2, Offset: 937},
+			{Hash: "aa5e3f802c6a6d3eb7eac845d2293dec38ccfff1", Pack: 0, Offset: 300},
+			{Hash: "ada33f6dd6675a666e762be13d3033305c8150f2", Pack: 2, Offset: 558},
+			{Hash: "af0209608bb116e47c1e2e7cb67b5b88f30e2fdd", Pack: 2, Offset: 318},
+			{Hash: "b73772b60534b66d2fc652345007876f88c9f2e6", Pack: 0, Offset: 284},
+			{Hash: "b9840c7383d22d5f9287a1e3f9e12c09a2a66489", Pack: 0, Offset: 826},
+			{Hash: "cdba493d5a0ad9cda7ff6f7ab0631e15e8cd0224", Pack: 0, Offset: 164},
+			{Hash: "ced19c0aa48fe73cf0c60b0b17d028b7718df545", Pack: 2, Offset: 396},
+			{Hash: "dbe358fc2a7f20e5f5f9719c91910bb3746f9a45", Pack: 0, Offset: 674},
+			{Hash: "e4bd5b0d0628ce07636454015cf4c7c8fe3eda56", Pack: 2, Offset: 163},
+			{Hash: "e5ca80afc4eb3d1fb891896cb163c57d1a16d2aa", Pack: 2, Offset: 579},
+		},
+	},
+	"git-f5b4ce165630126595cfea921c50a7b4f932f4f8": {
+		Version:      1,
+		ObjectFormat: "sha256",
+		Checksum:     "b771f7d1cf19f62ebd1c6a03d9ed33ba7be7220c84ae2dd2452880bff218a6f1",
+		Packs: []string{
+			"pack-0142a68c8fc758f812add2ecced08c51c1f0e09f5ef61e025b1f9621b9f1e60c.idx",
+			"pack-2edffc7b1efd9f01dbcf163c17fcc41e8762879ce452fc01c0328ae6ffd0218e.idx",
+			"pack-53c1e83f7b89c5d1185299f5359357fb65a6f282d31cec6c852a27ad62363c7f.idx",
+		},
+		PreferredPack: 1,
+		Chunks: []MIDXChunk{
+			{ID: "PNAM", Offset: 84, Size: 224},
+			{ID: "OIDF", Offset: 308, Size: 1024},
+			{ID: "OIDL", Offset: 1332, Size: 544},
+			{ID: "OOFF", Offset: 1876, Size: 136},
+			{ID: "RIDX", Offset: 2012, Size: 68},
+		},
+		Objects: []MIDXObject{
+			{Hash: "0c9ec24e09b7575940a0da37210bcdc64f9dac0a757e88e67a43875eea7acf07", Pack: 1, Offset: 518},
+			{Hash: "2e99bb3d41e18f7376afea56c5ab1029b3bd72ac68b2205066813cb6d5bb3739", Pack: 1, Offset: 12},
+			{Hash: "437dd9d653be2b8864fc1fec221e1b6ecd6cc5f2941afd8a21641aa6366d01bc", Pack: 0, Offset: 347},
+			{Hash: "5788eeb4f2d241c12237761a322fb436ae3ade5ed24bd94e6ec51005404104ba", Pack: 1, Offset: 765},
+			{Hash: "77bc89304aeb704d3e0bbfa832bf75ee3ff11f86aaabfd4b1440e5e5656c58d9", Pack: 0, Offset: 865},
+			{Hash: "7a56d35e2a1079acdd63fd1a643a4f0a449b657e5518c7f7cd20cf611b7b05b7", Pack: 0, Offset: 721},
+			{Hash: "b26d2015a5ba62d260ff292fdb56f254aa4b4231b72761fabc2be3dbe526e576", Pack: 1, Offset: 1144},
+			{Hash: "b9c7b2dac9906a5a44e1fd5cef6f525e504c69d267d70e70b81e64757f68255b", Pack: 1, Offset: 374},
+			{Hash: "c486022bf1bbc08c7685e9e558e75d0e2a17a84265bda005ba02eae554135636", Pack: 0, Offset: 331},
+			{Hash: "cc0fb4a54bc347ee8ce7ce52f14536fb5c677ae3af038b062bb98be4d8a33e70", Pack: 1, Offset: 192},
+			{Hash: "dabe8dc2e1b5e999839da66aa5cadf24d6252e30ebb241c225cf1d60cfca20cf", Pack: 0, Offset: 12},
+			{Hash: "dd97185c21068635ab1969698a60cb03568c30dda93cb85f2cd5e4d9bfbd0fa1", Pack: 0, Offset: 923},
+			{Hash: "e75c63ee7b6df93fc77d0d5baf66bbeea190577185341e81bc60e105626b3150", Pack: 2, Offset: 374},
+			{Hash: "e9d968f79f3c2290e42b83d595e52dc3190e6ea586710d21104a09f5ff0c4733", Pack: 0, Offset: 195},
+			{Hash: "f704bf950404badb02a8ab7bbe019b0321b91702f332f44c2dad18db03fbcd8b", Pack: 1, Offset: 621},
+			{Hash: "f97e35e935590d534e012cb2db06d2bf67eee00650b8b4c269a7ba3cf16351a0", Pack: 1, Offset: 786},
+			{Hash: "fa70251daf2d85ba44361c74759097da31e1d45cad0b42409efa587d8b1960a3", Pack: 0, Offset: 686},
+		},
+		ReverseIndex: []uint32{1, 9, 7, 0, 14, 3, 15, 6, 10, 13, 8, 2, 16, 5, 4, 11, 12},
+		Bitmap:       "multi-pack-index-b771f7d1cf19f62ebd1c6a03d9ed33ba7be7220c84ae2dd2452880bff218a6f1.bitmap",
+	},
+}
@@ -0,0 +1,165 @@
+package fixtures_test
+
+import (
+	"crypto/sha1" //nolint:gosec // git object format.
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"path"
+	"testing"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/util"
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/go-git/go-git-fixtures/v6/internal/idxfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiPackIndex(t *testing.T) {
+	t.Parallel()
+
+	mfs := fixtures.ByTag(fixtures.TagMultiPackIndex)
+	require.Len(t, mfs, 4)
+
+	mfs.Run(t, func(t *testing.T, f *fixtures.Fixture) {
+		t.Parallel()
+
+		m := f.MultiPackIndex()
+		require.NotNil(t, m)
+		assert.Equal(t, 1, m.Version)
+		assert.True(t, f.Is(fixtures.TagMIDXV1))
+		assert.Equal(t, f.ObjectFormat, m.ObjectFormat)
+
+		fs, err := f.DotGit()
+		require.NoError(t, err)
+
+		data, err := util.ReadFile(fs, "objects/pack/multi-pack-index")
+		require.NoError(t, err)
+
+		h := newHash(t, f.ObjectFormat)
+		h.Write(data[:len(data)-h.Size()])
+		assert.Equal(t, hex.EncodeToString(h.Sum(nil)), m.Checksum)
+
+		_, ok := m.Chunk("RIDX")
+		assert.Equal(t, f.Is(fixtures.TagMIDXRIDX), ok)
+		assert.Equal(t, ok, m.ReverseIndex != nil)
+		assert.Equal(t, ok, m.PreferredPack >= 0)
+
+		if f.Is(fixtures.TagMIDXBitmap) {
+			assertMIDXBitmap(t, fs, m)
+		} else {
+			assert.Empty(t, m.Bitmap)
+		}
+
+		last := m.Chunks[len(m.Chunks)-1]
+		assert.Equal(t, int64(len(data)-h.Size()), last.Offset+last.Size)
+
+		assertMIDXObjects(t, f, m)
+	})
+}
+
+// assertMIDXBitmap checks that the bitmap of m is named after its checksum
+// and has it in its header, which is how git ties a multi-pack bitmap to its
+// multi-pack-index, and that m has a preferred pack to order it by.
+func assertMIDXBitmap(t *testing.T, fs billy.Filesystem, m *fixtures.MultiPackIndex) {
+	t.Helper()
+
+	assert.Equal(t, "multi-pack-index-"+m.Checksum+".bitmap", m.Bitmap)
+	assert.GreaterOrEqual(t, m.PreferredPack, 0)
+
+	data, err := util.ReadFile(fs, path.Join("objects/pack", m.Bitmap))
+	require.NoError(t, err)
+
+	// BITM, version, options and entry count precede the checksum.
+	const headerSize = 12
+
+	require.Greater(t, len(data), headerSize+len(m.Checksum)/2)
+	assert.Equal(t, "BITM", string(data[:4]))
+	assert.Equal(t, m.Checksum, hex.EncodeToString(data[headerSize:headerSize+len(m.Checksum)/2]))
+}
+
+// assertMIDXObjects checks that the objects of m are those of the idx files of
+// its packs, each at the offset of its pack.
+func assertMIDXObjects(t *testing.T, f *fixtures.Fixture, m *fixtures.MultiPackIndex) {
+	t.Helper()
+
+	fs, err := f.DotGit()
+	require.NoError(t, err)
+
+	found := map[string]bool{}
+
+	for i, name := range m.Packs {
+		data, err := util.ReadFile(fs, path.Join("objects/pack", name))
+		require.NoError(t, err)
+
+		idx, err := idxfile.Decode(f.ObjectFormat, data)
+		require.NoError(t, err)
+
+		for _, e := range idx.Entries {
+			o, ok := m.Object(e.Hash)
+			require.True(t, ok, "object %s of %s is missing", e.Hash, name)
+
+			if o.Pack == i {
+				assert.Equal(t, e.Offset, o.Offset, e.Hash)
+
+				found[e.Hash] = true
+			}
+		}
+	}
+
+	assert.Len(t, found, len(m.Objects), "objects not found in their pack")
+
+	if m.ReverseIndex == nil {
+		return
+	}
+
+	// The pseudo-pack order starts with the preferred pack, by offset.
+	assert.Len(t, m.ReverseIndex, len(m.Objects))
+
+	first := m.Objects[m.ReverseIndex[0]]
+	assert.Equal(t, m.PreferredPack, first.Pack)
+
+	for i := 1; i < len(m.ReverseIndex); i++ {
+		prev, cur := m.Objects[m.ReverseIndex[i-1]], m.Objects[m.ReverseIndex[i]]
+		if cur.Pack == prev.Pack {
+			assert.Less(t, prev.Offset, cur.Offset)
+		}
+	}
+}
+
+func newHash(t *testing.T, format string) hash.Hash {
+	t.Helper()
+
+	switch format {
+	case "sha1":
+		return sha1.New() //nolint:gosec // git object format.
+	case "sha256":
+		return sha256.New()
+	}
+
+	require.Failf(t, "unknown object format", "%q", format)
+
+	return nil
+}
+
+func TestMultiPackIndexNone(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, fixtures.ByName("tags").MultiPackIndex())
+	assert.Nil(t, fixtures.ByName("basic-worktree").MultiPackIndex())
+}
+
+func TestMultiPackIndexIsCopy(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByName("midx-bitmap")
+
+	m := f.MultiPackIndex()
+	m.Objects[0].Offset = 0
+	m.ReverseIndex[0] = 0
+
+	want := f.MultiPackIndex()
+	assert.NotZero(t, want.Objects[0].Offset)
+	assert.NotEqual(t, m.ReverseIndex[0], want.ReverseIndex[0])
+}
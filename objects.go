@@ -0,0 +1,92 @@
+package fixtures
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/packfile"
+)
+
+//nolint:gochecknoglobals
+var objectsCache = newMetadataCache[[]Object]()
+
+// Object is the expected content of a single git object of a fixture.
+// Fields use plain types so go-git-fixtures does not depend on go-git.
+type Object struct {
+	// Hash is the hex-encoded object hash.
+	Hash string
+	// Type is the object type, using the same values as ScannerEntry.Type
+	// (1=commit, 2=tree, 3=blob, 4=tag). It is never a delta type.
+	Type int
+	// Size is the size of the object's uncompressed content.
+	Size int64
+	// Offset is the offset of the object in the fixture's packfile.
+	Offset int64
+	// DeltaBase is the hash of the object this one is deltified against in
+	// the packfile. Empty for objects stored in full.
+	DeltaBase string
+	// DeltaDepth is the length of the delta chain leading to this object,
+	// zero for objects stored in full.
+	DeltaDepth int
+
+	content []byte
+}
+
+// Reader returns a reader for the canonical uncompressed content of the
+// object, without the loose object header.
+func (o Object) Reader() io.Reader {
+	return bytes.NewReader(o.content)
+}
+
+// Objects returns the catalogue of every object in the fixture's packfile,
+// ordered by hash. Deltas are resolved, so each object carries its final type
+// and content.
+//
+// The catalogue is computed from the packfile on first use and cached.
+// Returns nil if the fixture has no packfile, or if it cannot be resolved on
+// its own (e.g. thin packs).
+func (f *Fixture) Objects() []Object {
+	objects, err := f.objects()
+	if err != nil {
+		return nil
+	}
+
+	return slices.Clone(objects)
+}
+
+func (f *Fixture) objects() ([]Object, error) {
+	name := f.packfilePath("pack")
+
+	return objectsCache.get(name, func() ([]Object, error) {
+		data, err := f.readFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		decoded, err := packfile.Decode(f.ObjectFormat, data)
+		if err != nil {
+			return nil, err
+		}
+
+		objects := make([]Object, 0, len(decoded))
+		for _, o := range decoded {
+			objects = append(objects, Object{
+				Hash:       o.Hash,
+				Type:       int(o.ResolvedType),
+				Size:       int64(len(o.Content)),
+				Offset:     o.Offset,
+				DeltaBase:  o.Base,
+				DeltaDepth: o.Depth,
+				content:    o.Content,
+			})
+		}
+
+		slices.SortFunc(objects, func(a, b Object) int {
+			return strings.Compare(a.Hash, b.Hash)
+		})
+
+		return objects, nil
+	})
+}
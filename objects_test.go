@@ -0,0 +1,102 @@
+package fixtures_test
+
+import (
+	"crypto/sha1" //nolint:gosec // sha1 is the default git object format.
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"testing"
+
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjects(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range fixtures.ByTag("packfile-entries") {
+		t.Run(f.PackfileHash, func(t *testing.T) {
+			t.Parallel()
+
+			objects := f.Objects()
+			require.NotEmpty(t, objects)
+
+			entries := f.Entries()
+			assert.Len(t, objects, len(entries))
+
+			names := map[int]string{1: "commit", 2: "tree", 3: "blob", 4: "tag"}
+
+			for _, o := range objects {
+				assert.Equal(t, entries[o.Hash], o.Offset)
+				require.Contains(t, names, o.Type)
+
+				content, err := io.ReadAll(o.Reader())
+				require.NoError(t, err)
+				assert.Len(t, content, int(o.Size))
+
+				var h hash.Hash = sha1.New() //nolint:gosec // sha1 is the default git object format.
+				if f.ObjectFormat == "sha256" {
+					h = sha256.New()
+				}
+
+				fmt.Fprintf(h, "%s %d\x00", names[o.Type], len(content))
+				h.Write(content)
+				assert.Equal(t, o.Hash, hex.EncodeToString(h.Sum(nil)))
+
+				if o.DeltaDepth > 0 {
+					assert.NotEmpty(t, o.DeltaBase)
+				} else {
+					assert.Empty(t, o.DeltaBase)
+				}
+			}
+		})
+	}
+}
+
+func TestObjectsBasic(t *testing.T) {
+	t.Parallel()
+
+	ofs := fixtures.Basic().ByTag("ofs-delta").ByTag("packfile-entries").One()
+	ref := fixtures.Basic().ByTag("ref-delta").One()
+	require.NotNil(t, ofs)
+	require.NotNil(t, ref)
+
+	ofsObjects := ofs.Objects()
+	refObjects := ref.Objects()
+	require.Len(t, ofsObjects, 31)
+	require.Len(t, refObjects, 31)
+
+	var maxDepth int
+
+	for i, o := range ofsObjects {
+		assert.Equal(t, o.Hash, refObjects[i].Hash)
+		assert.Equal(t, o.Type, refObjects[i].Type)
+		assert.Equal(t, o.Size, refObjects[i].Size)
+
+		a, err := io.ReadAll(o.Reader())
+		require.NoError(t, err)
+		b, err := io.ReadAll(refObjects[i].Reader())
+		require.NoError(t, err)
+		assert.Equal(t, a, b)
+
+		maxDepth = max(maxDepth, o.DeltaDepth)
+
+		if o.Hash == ofs.Head {
+			assert.Equal(t, 1, o.Type)
+			assert.Equal(t, "e8d3ffab552895c19b9fcf7aa264d277cde33881", o.DeltaBase)
+			assert.Equal(t, 1, o.DeltaDepth)
+		}
+	}
+
+	assert.Equal(t, 3, maxDepth)
+}
+
+func TestObjectsReturnsNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, fixtures.ByTag("worktree").One().Objects())
+	assert.Nil(t, fixtures.ByTag("thinpack").One().Objects())
+}
@@ -1,8 +1,11 @@
 package fixtures
 
 import (
+	"fmt"
 	"io"
-	"slices"
+	"os"
+	"sync"
+	"testing"
 
 	"github.com/go-git/go-billy/v6"
 	"github.com/go-git/go-billy/v6/osfs"
@@ -10,89 +13,156 @@ import (
 
 // OSFixture wraps a Fixture and provides OS filesystem-based access to fixture
 // files instead of using the embedded filesystem. This is useful when tests
-// require real filesystem operations to exercise a specific execution path.
+// require real filesystem operations to exercise a specific execution path,
+// such as those relying on file descriptors.
+//
+// Everything it returns is written under its dir, within a directory removed
+// by Cleanup.
 type OSFixture struct {
 	*Fixture
 
 	// dir is the base directory where temporary files will be created.
 	dir string
+	// work holds the files created by the fixture, and is shared with its
+	// clones.
+	work *workDir
 }
 
 // NewOSFixture converts a Fixture which is based on embedfs, into
 // an OS-based fixture. The dir parameter specifies the base directory
 // where temporary files will be created when accessing packfiles, indexes,
-// or rev files.
+// or rev files, and where DotGit and Worktree are extracted.
+//
+// The caller is responsible for calling Cleanup, see NewOSFixtureT.
 func NewOSFixture(f *Fixture, dir string) *OSFixture {
 	return &OSFixture{
 		Fixture: f,
 		dir:     dir,
+		work:    &workDir{mu: sync.Mutex{}, path: ""},
 	}
 }
 
+// NewOSFixtureT returns an OSFixture creating its files in a temporary
+// directory of tb, which are removed by Cleanup once tb completes.
+func NewOSFixtureT(tb testing.TB, f *Fixture) *OSFixture {
+	tb.Helper()
+
+	osf := NewOSFixture(f, tb.TempDir())
+	tb.Cleanup(func() {
+		err := osf.Cleanup()
+		if err != nil {
+			tb.Errorf("cleaning up fixture %s: %v", f.runName(), err)
+		}
+	})
+
+	return osf
+}
+
+// Cleanup removes the files created by the fixture and its clones. Files
+// and filesystems returned before must not be used afterwards.
+func (f *OSFixture) Cleanup() error {
+	return f.work.remove()
+}
+
 // Is reports whether the fixture has the specified tag.
-func (f *OSFixture) Is(tag string) bool {
+func (f *OSFixture) Is(tag Tag) bool {
 	return f.Fixture.Is(tag)
 }
 
-// Packfile returns the packfile as an OS-based file.
-func (f *OSFixture) Packfile() (billy.File, error) {
-	file, err := f.Fixture.Packfile()
+// Packfile returns the packfile as an OS-based file. Of opts, only
+// WithFaults applies.
+func (f *OSFixture) Packfile(opts ...Option) (billy.File, error) {
+	return f.openFile(f.packfilePath("pack"), opts)
+}
+
+// Idx returns the packfile index as an OS-based file. Of opts, only
+// WithFaults applies.
+func (f *OSFixture) Idx(opts ...Option) (billy.File, error) {
+	return f.openFile(f.packfilePath("idx"), opts)
+}
+
+// Rev returns the reverse index file as an OS-based file. Of opts, only
+// WithFaults applies.
+func (f *OSFixture) Rev(opts ...Option) (billy.File, error) {
+	return f.openFile(f.packfilePath("rev"), opts)
+}
+
+// DotGit extracts the .git directory into a new directory on disk, as
+// WithTargetDir does. It can be overridden by opts, e.g. with WithMemFS.
+func (f *OSFixture) DotGit(opts ...Option) (billy.Filesystem, error) {
+	target, err := f.mkdirTemp("dotgit-")
 	if err != nil {
 		return nil, err
 	}
 
-	return embedToOsfs(f.dir, file)
+	return f.Fixture.DotGit(append([]Option{target}, opts...)...)
+}
+
+// Clone creates a deep copy of the OSFixture, sharing the files cleaned up
+// by Cleanup.
+func (f *OSFixture) Clone() *OSFixture {
+	nf := &OSFixture{
+		Fixture: f.Fixture.Clone(),
+		dir:     f.dir,
+		work:    f.work,
+	}
+
+	return nf
 }
 
-// Idx returns the packfile index as an OS-based file.
-func (f *OSFixture) Idx() (billy.File, error) {
-	file, err := f.Fixture.Idx()
+// Worktree extracts the worktree into a new directory on disk, as
+// WithTargetDir does. It can be overridden by opts, e.g. with WithMemFS.
+func (f *OSFixture) Worktree(opts ...Option) (billy.Filesystem, error) {
+	target, err := f.mkdirTemp("worktree-")
 	if err != nil {
 		return nil, err
 	}
 
-	return embedToOsfs(f.dir, file)
+	return f.Fixture.Worktree(append([]Option{target}, opts...)...)
 }
 
-// Rev returns the reverse index file as an OS-based file.
-func (f *OSFixture) Rev() (billy.File, error) {
-	file, err := f.Fixture.Rev()
+// openFile copies the data file name into a temporary file, wrapped as set
+// by WithFaults.
+func (f *OSFixture) openFile(name string, opts []Option) (billy.File, error) {
+	o := newOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	file, err := f.Fixture.openFile(name, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return embedToOsfs(f.dir, file)
-}
+	dir, err := f.work.get(f.dir)
+	if err != nil {
+		file.Close()
 
-// DotGit returns the .git directory filesystem. This delegates to the
-// underlying Fixture's DotGit method.
-func (f *OSFixture) DotGit(opts ...Option) (billy.Filesystem, error) {
-	return f.Fixture.DotGit(opts...)
-}
+		return nil, err
+	}
 
-// Clone creates a deep copy of the OSFixture.
-func (f *OSFixture) Clone() *OSFixture {
-	nf := &OSFixture{
-		Fixture: &Fixture{
-			URL:          f.URL,
-			DotGitHash:   f.DotGitHash,
-			Head:         f.Head,
-			PackfileHash: f.PackfileHash,
-			WorktreeHash: f.WorktreeHash,
-			ObjectsCount: f.ObjectsCount,
-			Tags:         slices.Clone(f.Tags),
-			ObjectFormat: f.ObjectFormat,
-		},
-		dir: f.dir,
+	out, err := embedToOsfs(dir, file)
+	if err != nil {
+		return nil, err
 	}
 
-	return nf
+	return o.withFileFaults(out, name), nil
 }
 
-// Worktree returns the worktree filesystem. This delegates to the
-// underlying Fixture's Worktree method.
-func (f *OSFixture) Worktree(opts ...Option) (billy.Filesystem, error) {
-	return f.Fixture.Worktree(opts...)
+// mkdirTemp creates a new directory for an extraction, returned as a
+// WithTargetDir option.
+func (f *OSFixture) mkdirTemp(prefix string) (Option, error) {
+	dir, err := f.work.get(f.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := os.MkdirTemp(dir, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return WithTargetDir(func() string { return target }), nil
 }
 
 func embedToOsfs(dir string, f billy.File) (billy.File, error) {
@@ -106,14 +176,58 @@ func embedToOsfs(dir string, f billy.File) (billy.File, error) {
 	}
 
 	_, err = io.Copy(out, f)
-	if err != nil {
-		return nil, err
+	if err == nil {
+		_, err = out.Seek(0, io.SeekStart)
 	}
 
-	_, err = out.Seek(0, io.SeekStart)
 	if err != nil {
+		out.Close()
+
 		return nil, err
 	}
 
 	return out, nil
 }
+
+// workDir is a directory created on first use, holding the files of an
+// OSFixture.
+type workDir struct {
+	mu   sync.Mutex
+	path string
+}
+
+// get returns the path of the directory, creating it in parent if needed.
+func (d *workDir) get(parent string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.path == "" {
+		path, err := os.MkdirTemp(parent, "osfixture-")
+		if err != nil {
+			return "", fmt.Errorf("creating fixture directory: %w", err)
+		}
+
+		d.path = path
+	}
+
+	return d.path, nil
+}
+
+// remove removes the directory, if created, and everything it holds.
+func (d *workDir) remove() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.path == "" {
+		return nil
+	}
+
+	err := os.RemoveAll(d.path)
+	if err != nil {
+		return err
+	}
+
+	d.path = ""
+
+	return nil
+}
@@ -2,6 +2,9 @@ package fixtures_test
 
 import (
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	fixtures "github.com/go-git/go-git-fixtures/v6"
@@ -117,3 +120,88 @@ func TestOSFixtureDotGit(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotEmpty(t, files)
 }
+
+func TestOSFixtureOnDisk(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	osf := fixtures.NewOSFixture(fixtures.ByName("basic-ofs-delta"), dir)
+
+	dotgit, err := osf.DotGit()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(dotgit.Root(), dir), dotgit.Root())
+
+	_, err = os.Stat(filepath.Join(dotgit.Root(), "HEAD"))
+	require.NoError(t, err)
+
+	other, err := osf.DotGit()
+	require.NoError(t, err)
+	assert.NotEqual(t, dotgit.Root(), other.Root())
+
+	wt, err := fixtures.NewOSFixture(fixtures.ByName("basic-worktree"), dir).Worktree()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(wt.Root(), dir), wt.Root())
+
+	_, err = os.Stat(filepath.Join(wt.Root(), ".git", "HEAD"))
+	require.NoError(t, err)
+
+	file, err := osf.Packfile()
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	fd, ok := file.(interface{ Fd() (uintptr, bool) })
+	require.True(t, ok, "packfile has no file descriptor")
+
+	_, ok = fd.Fd()
+	assert.True(t, ok)
+}
+
+func TestOSFixtureCleanup(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	osf := fixtures.NewOSFixture(fixtures.ByName("basic-ofs-delta"), dir)
+	clone := osf.Clone()
+
+	dotgit, err := osf.DotGit()
+	require.NoError(t, err)
+
+	file, err := clone.Idx()
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	require.NoError(t, osf.Cleanup())
+
+	_, err = os.Stat(dotgit.Root())
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// The fixture can still be used after a cleanup.
+	_, err = osf.DotGit()
+	require.NoError(t, err)
+	require.NoError(t, osf.Cleanup())
+}
+
+func TestNewOSFixtureT(t *testing.T) {
+	t.Parallel()
+
+	var root string
+
+	t.Run("fixture", func(t *testing.T) { //nolint:paralleltest // checked after it completes.
+		osf := fixtures.NewOSFixtureT(t, fixtures.ByName("basic-ofs-delta"))
+
+		fs, err := osf.DotGit(fixtures.WithRestrictedPermissions(t))
+		require.NoError(t, err)
+
+		root = fs.Root()
+	})
+
+	require.NotEmpty(t, root)
+
+	_, err := os.Stat(root)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
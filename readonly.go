@@ -0,0 +1,114 @@
+package fixtures
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-git-fixtures/v6/internal/readonly"
+)
+
+// writeBits are the permission bits removed by WithRestrictedPermissions.
+const writeBits = 0o222
+
+// WithReadOnly returns the option of returning a read-only view of the
+// fixture, where every call which would modify it, such as Create, OpenFile
+// for writing, Rename, Remove or MkdirAll, fails with an error matching
+// os.ErrPermission and billy.ErrReadOnly.
+//
+// The view applies to any filesystem, and does not change the permissions
+// of extracted files, see WithRestrictedPermissions for that.
+func WithReadOnly() Option {
+	return func(o *options) {
+		o.readOnly = true
+	}
+}
+
+// WithRestrictedPermissions returns the option of removing the write
+// permissions of the files and directories extracted by WithTargetDir, so
+// that the OS rejects any change to them, as on read-only media. The
+// permissions are restored when tb and its subtests complete, so that the
+// target dir can be removed, e.g. by t.TempDir.
+//
+// It has no effect on memfs, which does not enforce permissions, nor on
+// processes bypassing them, such as those running as root.
+func WithRestrictedPermissions(tb testing.TB) Option {
+	return func(o *options) {
+		o.restrictPermissions = tb
+	}
+}
+
+// wrap applies the options changing the filesystem fs once the fixture is
+// extracted into it.
+func (o *options) wrap(fs billy.Filesystem) (billy.Filesystem, error) {
+	// Only WithTargetDir extracts the fixture outside of memory.
+	if o.restrictPermissions != nil && !o.overlay {
+		err := restrictPermissions(o.restrictPermissions, fs.Root())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if o.readOnly {
+		fs = readonly.New(fs)
+	}
+
+	return o.withFaults(fs), nil
+}
+
+// restrictPermissions removes the write permissions of dir and everything
+// it holds, and restores them once tb completes. Symbolic links are left
+// alone, as their permissions cannot be changed on most systems.
+func restrictPermissions(tb testing.TB, dir string) error {
+	type change struct {
+		path string
+		mode fs.FileMode
+	}
+
+	var changes []change
+
+	// The cleanup is registered after dir is created, so that it runs before
+	// its removal by t.TempDir.
+	tb.Cleanup(func() {
+		for _, c := range slices.Backward(changes) {
+			err := os.Chmod(c.path, c.mode)
+			if err != nil {
+				tb.Errorf("restoring permissions of %s: %v", c.path, err)
+			}
+		}
+	})
+
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		mode := fi.Mode().Perm()
+		if mode&writeBits == 0 {
+			return nil
+		}
+
+		// Directories are walked before their content, which can still be
+		// read and changed by their owner without write permission on them.
+		err = os.Chmod(path, mode&^writeBits)
+		if err != nil {
+			return err
+		}
+
+		changes = append(changes, change{path: path, mode: mode})
+
+		return nil
+	})
+}
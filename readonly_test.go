@@ -0,0 +1,102 @@
+package fixtures_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/util"
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithReadOnly(t *testing.T) {
+	t.Parallel()
+
+	for name, opts := range map[string][]fixtures.Option{
+		"memfs":      nil,
+		"target dir": {fixtures.WithTargetDir(t.TempDir)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			fs, err := fixtures.ByName("basic-ofs-delta").DotGit(append(opts, fixtures.WithReadOnly())...)
+			require.NoError(t, err)
+
+			_, err = util.ReadFile(fs, "HEAD")
+			require.NoError(t, err)
+
+			err = util.WriteFile(fs, "HEAD", []byte("ref: refs/heads/other\n"), 0o644)
+			require.ErrorIs(t, err, os.ErrPermission)
+			require.ErrorIs(t, fs.Remove("HEAD"), os.ErrPermission)
+			require.ErrorIs(t, fs.MkdirAll("refs/heads/new", 0o755), os.ErrPermission)
+		})
+	}
+}
+
+func TestWithReadOnlyWorktreeDotGit(t *testing.T) {
+	t.Parallel()
+
+	fs, err := fixtures.ByName("basic-worktree").DotGit(fixtures.WithReadOnly())
+	require.NoError(t, err)
+
+	_, err = fs.Create("index.lock")
+	require.ErrorIs(t, err, os.ErrPermission)
+}
+
+func TestWithRestrictedPermissions(t *testing.T) {
+	t.Parallel()
+
+	var root string
+
+	t.Run("restricted", func(t *testing.T) { //nolint:paralleltest // checked after it completes.
+		// t.TempDir removes the directory once the subtest completes, which
+		// fails unless permissions are restored before.
+		fs, err := fixtures.ByName("basic-ofs-delta").DotGit(
+			fixtures.WithTargetDir(t.TempDir),
+			fixtures.WithRestrictedPermissions(t),
+		)
+		require.NoError(t, err)
+
+		root = fs.Root()
+		assertNotWritable(t, fs, "")
+		assertNotWritable(t, fs, "HEAD")
+		assertNotWritable(t, fs, "objects/pack")
+
+		if os.Geteuid() == 0 {
+			t.Log("running as root, permissions are not enforced")
+
+			return
+		}
+
+		err = util.WriteFile(fs, "HEAD", []byte("ref: refs/heads/other\n"), 0o644)
+		require.ErrorIs(t, err, os.ErrPermission)
+
+		_, err = fs.Create("new")
+		require.ErrorIs(t, err, os.ErrPermission)
+	})
+
+	require.NotEmpty(t, root)
+
+	_, err := os.Stat(root)
+	require.ErrorIs(t, err, os.ErrNotExist, "target dir not removed")
+}
+
+func TestWithRestrictedPermissionsMemFS(t *testing.T) {
+	t.Parallel()
+
+	fs, err := fixtures.ByName("basic-ofs-delta").DotGit(fixtures.WithRestrictedPermissions(t))
+	require.NoError(t, err)
+
+	require.NoError(t, util.WriteFile(fs, "HEAD", []byte("ref: refs/heads/other\n"), 0o644))
+}
+
+func assertNotWritable(t *testing.T, fs billy.Filesystem, name string) {
+	t.Helper()
+
+	fi, err := os.Stat(filepath.Join(fs.Root(), name))
+	require.NoError(t, err)
+	assert.Zero(t, fi.Mode().Perm()&0o222, "%s is writable: %s", name, fi.Mode())
+}
@@ -0,0 +1,50 @@
+package fixtures
+
+import "slices"
+
+//go:generate go run ./internal/cmd/genmeta
+
+// Reference is an expected reference of a fixture's .git directory.
+// Fields use plain types so go-git-fixtures does not depend on go-git.
+type Reference struct {
+	// Name is the full reference name, e.g. HEAD or refs/heads/master.
+	Name string
+	// Target is the hex-encoded hash the reference points to. Empty for
+	// symbolic references.
+	Target string
+	// SymbolicTarget is the name of the reference a symbolic reference
+	// points to, e.g. refs/heads/master for HEAD. Empty otherwise.
+	SymbolicTarget string
+	// Peeled is the hex-encoded hash of the object an annotated tag
+	// ultimately points to. Empty for references not pointing to a tag.
+	Peeled string
+	// Packed reports whether the reference is stored in packed-refs.
+	Packed bool
+}
+
+// Refs returns the expected references of the fixture's .git directory,
+// ordered by name. It includes loose and packed references as well as
+// symbolic ones such as HEAD, regardless of the reference storage format
+// (files or reftable) of the fixture.
+//
+// References are extracted from the archives when generating the package.
+// Returns nil if the fixture has no .git directory.
+func (f *Fixture) Refs() []Reference {
+	var key string
+
+	switch {
+	case f.DotGitHash != "":
+		key = "git-" + f.DotGitHash
+	case f.WorktreeHash != "":
+		key = "worktree-" + f.WorktreeHash
+	default:
+		return nil
+	}
+
+	refs, ok := fixtureRefs[key]
+	if !ok {
+		return nil
+	}
+
+	return slices.Clone(refs)
+}
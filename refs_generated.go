@@ -0,0 +1,212 @@
+// Code generated by internal/cmd/genmeta. DO NOT EDIT.
+
+package fixtures
+
+//nolint:gochecknoglobals
+var fixtureRefs = map[string][]Reference{
+	"git-00a1fc100787506f842e55511994f08df2c2cd66": {
+		{Name: "HEAD", Target: "b9d69064b190e7aedccf84731ca1d917871f8a1c"},
+		{Name: "refs/heads/master", Target: "d2dc5ac04916e156018db4482c40c39b894090e9", Packed: true},
+		{Name: "refs/heads/merges/1", Target: "b29328491a0682c259bcce28741eac71f3499f7d", Packed: true},
+		{Name: "refs/heads/merges/2", Target: "d2dc5ac04916e156018db4482c40c39b894090e9", Packed: true},
+		{Name: "refs/heads/merges/3", Target: "6f6c5d2be7852c782be1dd13e36496dd7ad39560", Packed: true},
+	},
+	"git-21504f6d2cc2ef0c9d6ebb8802c7b49abae40c1a": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", Packed: true},
+		{Name: "refs/remotes/origin/HEAD", SymbolicTarget: "refs/remotes/origin/master"},
+		{Name: "refs/remotes/origin/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", Packed: true},
+	},
+	"git-26baa505b9f6fb2024b9999c140b75514718c988": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/dev", Target: "25ca6c810c08482d61113fbcaaada38bb59093a8"},
+		{Name: "refs/heads/feature", Target: "d1b0093698e398d596ef94d646c4db37e8d1e970"},
+		{Name: "refs/heads/master", Target: "dce0e0c20d701c3d260146e443d6b3b079505191"},
+		{Name: "refs/tags/A", Target: "29740cfaf0c2ee4bb532dba9e80040ca738f367c"},
+		{Name: "refs/tags/AB", Target: "31a7e081a28f149ee98ffd13ba1a6d841a5f46fd"},
+		{Name: "refs/tags/B", Target: "2c84807970299ba98951c65fe81ebbaac01030f0"},
+		{Name: "refs/tags/C", Target: "8b72fabdc4222c3ff965bc310ded788c601c50ed"},
+		{Name: "refs/tags/CD1", Target: "4709e13a3cbb300c2b8a917effda776e1b8955c7"},
+		{Name: "refs/tags/CD2", Target: "38468e274e91e50ffb637b88a1954ab6193fe974"},
+		{Name: "refs/tags/D", Target: "14777cf3e209334592fbfd0b878f6868394db836"},
+		{Name: "refs/tags/G", Target: "d1b0093698e398d596ef94d646c4db37e8d1e970"},
+		{Name: "refs/tags/GQ1", Target: "ccaaa99c21dad7e9f392c36ae8cb72dc63bed458"},
+		{Name: "refs/tags/GQ2", Target: "806824d4778e94fe7c3244e92a9cd07090c9ab54"},
+		{Name: "refs/tags/M", Target: "bb355b64e18386dbc3af63dfd09c015c44cbd9b6"},
+		{Name: "refs/tags/N", Target: "d64b894762ab5f09e2b155221b90c18bd0637236"},
+		{Name: "refs/tags/P", Target: "ff84393134864cf9d3a9853a81bde81778bd5805"},
+		{Name: "refs/tags/Q", Target: "dce0e0c20d701c3d260146e443d6b3b079505191"},
+		{Name: "refs/tags/S", Target: "628f1a42b70380ed05734bf01b468b46206ef1ea"},
+	},
+	"git-40143428b59fe03546fabba0603268bba3b3c58b": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/main"},
+		{Name: "refs/heads/changes", Target: "b2755b63e92d79410f28a33bef90107789c63a139121a457920c668d5805e974", Packed: true},
+		{Name: "refs/heads/main", Target: "b2755b63e92d79410f28a33bef90107789c63a139121a457920c668d5805e974", Packed: true},
+		{Name: "refs/remotes/origin/main", Target: "b2755b63e92d79410f28a33bef90107789c63a139121a457920c668d5805e974", Packed: true},
+	},
+	"git-4870d54b5b04e43da8cf99ceec179d9675494af8": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "1980fcf55330d9d94c34abee5ab734afecf96aba"},
+		{Name: "refs/remotes/origin/HEAD", SymbolicTarget: "refs/remotes/origin/master"},
+		{Name: "refs/remotes/origin/branch", Target: "e8d3ffab552895c19b9fcf7aa264d277cde33881", Packed: true},
+		{Name: "refs/remotes/origin/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", Packed: true},
+		{Name: "refs/remotes/t/master", Target: "f72835db2eeacb70969ef2164c30a2613c40609c"},
+	},
+	"git-4e7600af05c3356e8b142263e127b76f010facfc": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "1980fcf55330d9d94c34abee5ab734afecf96aba"},
+		{Name: "refs/remotes/origin/HEAD", SymbolicTarget: "refs/remotes/origin/master"},
+		{Name: "refs/remotes/origin/branch", Target: "e8d3ffab552895c19b9fcf7aa264d277cde33881", Packed: true},
+		{Name: "refs/remotes/origin/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", Packed: true},
+		{Name: "refs/remotes/t/master", Target: "f72835db2eeacb70969ef2164c30a2613c40609c"},
+	},
+	"git-5f620e4b3194c0c4a77fbd17f501030a441f54d4": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5"},
+		{Name: "refs/remotes/origin/HEAD", SymbolicTarget: "refs/remotes/origin/master"},
+		{Name: "refs/remotes/origin/branch", Target: "e8d3ffab552895c19b9fcf7aa264d277cde33881"},
+		{Name: "refs/remotes/origin/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5"},
+	},
+	"git-77b6511a6e67c99162ebcecd2763a9a19a7ad429": {
+		{Name: "HEAD", Target: "ec6f456c0e8c7058a29611429965aa05c190b54b"},
+	},
+	"git-78c5fb882e76286d8201016cffee63ea7060a0c2": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/1"},
+		{Name: "refs/heads/1", Target: "caf05fe371a5a6feab588a73ebd9ac73abdd072c", Packed: true},
+		{Name: "refs/heads/2", Target: "04fffad6eacd4512554cb22ca3a0d6b8a38a96cc", Packed: true},
+		{Name: "refs/heads/3", Target: "058cec4b81e8f0a9c3763e0671bbfba0666a4b33", Packed: true},
+		{Name: "refs/heads/branch", Target: "e8d3ffab552895c19b9fcf7aa264d277cde33881", Packed: true},
+		{Name: "refs/heads/functionalityOne", Target: "ca858bfd043ac70bf532d53a4031be0cdf7483b4", Packed: true},
+		{Name: "refs/heads/functionalityTwo", Target: "79b3db5091672bcb9da2704a2d7b269bcd1ef36f", Packed: true},
+		{Name: "refs/heads/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", Packed: true},
+		{Name: "refs/heads/rootReference", Target: "a135c3e77219a8eaf166a643f6ce3192e97b7e5e", Packed: true},
+		{Name: "refs/remotes/origin/HEAD", SymbolicTarget: "refs/remotes/origin/master"},
+		{Name: "refs/remotes/origin/branch", Target: "e8d3ffab552895c19b9fcf7aa264d277cde33881", Packed: true},
+		{Name: "refs/remotes/origin/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", Packed: true},
+		{Name: "refs/tags/v1.0.0", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", Packed: true},
+	},
+	"git-7a725350b88b05ca03541b59dd0649fda7f521f2": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/branch", Target: "e8d3ffab552895c19b9fcf7aa264d277cde33881"},
+		{Name: "refs/heads/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", Packed: true},
+		{Name: "refs/remotes/origin/HEAD", SymbolicTarget: "refs/remotes/origin/master"},
+		{Name: "refs/remotes/origin/branch", Target: "e8d3ffab552895c19b9fcf7aa264d277cde33881", Packed: true},
+		{Name: "refs/remotes/origin/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", Packed: true},
+		{Name: "refs/tags/v1.0.0", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5"},
+	},
+	"git-7cbde0ca02f13aedd5ec8b358ca17b1c0bf5ee64": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5"},
+		{Name: "refs/remotes/origin/branch", Target: "e8d3ffab552895c19b9fcf7aa264d277cde33881"},
+		{Name: "refs/remotes/origin/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5"},
+	},
+	"git-7cdeabb9642835c4f003aa38e6cfbe8c316d2e89": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/feature", Target: "4595b9e6c1266f35b781e740456a348290b26f1d"},
+		{Name: "refs/heads/master", Target: "e4bd5b0d0628ce07636454015cf4c7c8fe3eda56"},
+		{Name: "refs/tags/v1.0", Target: "26af8798dbbd98265b2bb23c81b4f4ea46f065c4", Peeled: "e4bd5b0d0628ce07636454015cf4c7c8fe3eda56"},
+	},
+	"git-827d08501b81099c1db24f4b309b90df149ab0a2": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/feature", Target: "2e99bb3d41e18f7376afea56c5ab1029b3bd72ac68b2205066813cb6d5bb3739"},
+		{Name: "refs/heads/master", Target: "cc0fb4a54bc347ee8ce7ce52f14536fb5c677ae3af038b062bb98be4d8a33e70"},
+		{Name: "refs/tags/v1.0", Target: "e75c63ee7b6df93fc77d0d5baf66bbeea190577185341e81bc60e105626b3150", Peeled: "cc0fb4a54bc347ee8ce7ce52f14536fb5c677ae3af038b062bb98be4d8a33e70"},
+	},
+	"git-935e5ac17c41c309c356639816ea0694a568c484": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "1980fcf55330d9d94c34abee5ab734afecf96aba"},
+		{Name: "refs/remotes/origin/HEAD", SymbolicTarget: "refs/remotes/origin/master"},
+		{Name: "refs/remotes/origin/branch", Target: "e8d3ffab552895c19b9fcf7aa264d277cde33881", Packed: true},
+		{Name: "refs/remotes/origin/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", Packed: true},
+		{Name: "refs/remotes/t/master", Target: "f72835db2eeacb70969ef2164c30a2613c40609c"},
+	},
+	"git-9796b2f5d699a996e418ce3d43e14f819ce39133": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/feature", Target: "4595b9e6c1266f35b781e740456a348290b26f1d"},
+		{Name: "refs/heads/master", Target: "e4bd5b0d0628ce07636454015cf4c7c8fe3eda56"},
+		{Name: "refs/tags/v1.0", Target: "26af8798dbbd98265b2bb23c81b4f4ea46f065c4", Peeled: "e4bd5b0d0628ce07636454015cf4c7c8fe3eda56"},
+	},
+	"git-ab06771a67110b976953d34400d4dbc465ccd2d9": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5"},
+		{Name: "refs/remotes/origin/HEAD", SymbolicTarget: "refs/remotes/origin/master"},
+		{Name: "refs/remotes/origin/branch", Target: "e8d3ffab552895c19b9fcf7aa264d277cde33881", Packed: true},
+		{Name: "refs/remotes/origin/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", Packed: true},
+	},
+	"git-bf3fedcc8e20fd0dec9172987ceea0038d17b516": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+	},
+	"git-c0c7c57ab1753ddbd26cc45322299ddd12842794": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "f7b877701fbf855b44c0a9e86f3fdce2c298b07f"},
+		{Name: "refs/remotes/origin/HEAD", SymbolicTarget: "refs/remotes/origin/master"},
+		{Name: "refs/remotes/origin/master", Target: "f7b877701fbf855b44c0a9e86f3fdce2c298b07f", Packed: true},
+		{Name: "refs/tags/annotated-tag", Target: "b742a2a9fa0afcfa9a6fad080980fbc26b007c69", Peeled: "f7b877701fbf855b44c0a9e86f3fdce2c298b07f", Packed: true},
+		{Name: "refs/tags/blob-tag", Target: "fe6cb94756faa81e5ed9240f9191b833db5f40ae", Peeled: "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391", Packed: true},
+		{Name: "refs/tags/commit-tag", Target: "ad7897c0fb8e7d9a9ba41fa66072cf06095a6cfc", Peeled: "f7b877701fbf855b44c0a9e86f3fdce2c298b07f", Packed: true},
+		{Name: "refs/tags/lightweight-tag", Target: "f7b877701fbf855b44c0a9e86f3fdce2c298b07f", Packed: true},
+		{Name: "refs/tags/tree-tag", Target: "152175bf7e5580299fa1f0ba41ef6474cc043b70", Peeled: "70846e9a10ef7b41064b40f07713d5b8b9a8fc73", Packed: true},
+	},
+	"git-c20badf43d2495f93b42cb3ea98ed04651510617da9b56d4e07c5837ec08f93d": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/branch", Target: "b8bdc620cb4859cf6e48768fd67f526229f3a57aa417740024bf7e6af5fdb04c", Packed: true},
+		{Name: "refs/heads/master", Target: "4fef4adac3be863b9b94613016bdd8e53f67f6d7577234e028bc9d24c5a6a27c", Packed: true},
+		{Name: "refs/pull/1/head", Target: "030d8320428f364839a75c1fe8d4cc2cdada2b683dcaffcc94d9770640302dd1", Packed: true},
+		{Name: "refs/pull/2/head", Target: "011218223f6e9e4a7f7ed704999158d6a3d080bedff536983c0d0e03d262c664", Packed: true},
+		{Name: "refs/pull/2/merge", Target: "6e8d71fbfd367c34968d31ef8886929a9862b02de4616bfc569583b3f5a76808", Packed: true},
+		{Name: "refs/remotes/origin/branch", Target: "b8bdc620cb4859cf6e48768fd67f526229f3a57aa417740024bf7e6af5fdb04c", Packed: true},
+		{Name: "refs/remotes/origin/master", Target: "4fef4adac3be863b9b94613016bdd8e53f67f6d7577234e028bc9d24c5a6a27c", Packed: true},
+	},
+	"git-cf717ccadce761d60bb4a8557a7b9a2efd23816a": {
+		{Name: "HEAD", Target: "b9d69064b190e7aedccf84731ca1d917871f8a1c"},
+		{Name: "refs/heads/master", Target: "d2dc5ac04916e156018db4482c40c39b894090e9", Packed: true},
+		{Name: "refs/heads/merges/1", Target: "b29328491a0682c259bcce28741eac71f3499f7d", Packed: true},
+		{Name: "refs/heads/merges/2", Target: "d2dc5ac04916e156018db4482c40c39b894090e9", Packed: true},
+		{Name: "refs/heads/merges/3", Target: "6f6c5d2be7852c782be1dd13e36496dd7ad39560", Packed: true},
+	},
+	"git-df6781fd40b8f4911d70ce71f8387b991615cd6d": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "1980fcf55330d9d94c34abee5ab734afecf96aba"},
+		{Name: "refs/remotes/origin/HEAD", SymbolicTarget: "refs/remotes/origin/master"},
+		{Name: "refs/remotes/origin/branch", Target: "e8d3ffab552895c19b9fcf7aa264d277cde33881", Packed: true},
+		{Name: "refs/remotes/origin/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", Packed: true},
+		{Name: "refs/remotes/t/master", Target: "f72835db2eeacb70969ef2164c30a2613c40609c"},
+	},
+	"git-e1580a78f7d36791249df76df8a2a2613d629902": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "70bade703ce556c2c7391a8065c45c943e8b6bc3"},
+		{Name: "refs/remotes/origin/HEAD", SymbolicTarget: "refs/remotes/origin/master"},
+		{Name: "refs/remotes/origin/master", Target: "70bade703ce556c2c7391a8065c45c943e8b6bc3", Packed: true},
+	},
+	"git-f5b4ce165630126595cfea921c50a7b4f932f4f8": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/feature", Target: "2e99bb3d41e18f7376afea56c5ab1029b3bd72ac68b2205066813cb6d5bb3739"},
+		{Name: "refs/heads/master", Target: "cc0fb4a54bc347ee8ce7ce52f14536fb5c677ae3af038b062bb98be4d8a33e70"},
+		{Name: "refs/tags/v1.0", Target: "e75c63ee7b6df93fc77d0d5baf66bbeea190577185341e81bc60e105626b3150", Peeled: "cc0fb4a54bc347ee8ce7ce52f14536fb5c677ae3af038b062bb98be4d8a33e70"},
+	},
+	"worktree-8b4d55c85677b6b94bef2e46832ed2174ed6ecaf": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "b685400c1f9316f350965a5993d350bc746b0bf4"},
+		{Name: "refs/remotes/origin/master", Target: "b685400c1f9316f350965a5993d350bc746b0bf4"},
+	},
+	"worktree-8be1f59f7aa3fc70ea4ff9f8fdb49b2c977222eb": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "fba8337cd0c58c7d516ba493978af61cf4e59652", Packed: true},
+	},
+	"worktree-d2e42ddd68eacbb6034e7724e0dd4117ff1f01ee": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5"},
+		{Name: "refs/remotes/origin/HEAD", SymbolicTarget: "refs/remotes/origin/master"},
+		{Name: "refs/remotes/origin/branch", Target: "e8d3ffab552895c19b9fcf7aa264d277cde33881", Packed: true},
+		{Name: "refs/remotes/origin/master", Target: "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", Packed: true},
+	},
+	"worktree-df8b2731b8978b5efca95cc2eef5ee43915e0945e3310653b0ef0c00453d67c4": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/master"},
+		{Name: "refs/heads/master", Target: "918b510390028a69be8fb6dff9dd2b72bb26c9456992623ad2e5f3f18363e6df", Packed: true},
+		{Name: "refs/remotes/origin/master", Target: "918b510390028a69be8fb6dff9dd2b72bb26c9456992623ad2e5f3f18363e6df", Packed: true},
+	},
+	"worktree-e3b91f99d8d050cac81d84fbef89172f58eeb745": {
+		{Name: "HEAD", SymbolicTarget: "refs/heads/main"},
+		{Name: "refs/heads/main", Target: "786dafbd351e587da1ae97e5fb9fbdf868b4a28f"},
+	},
+}
@@ -0,0 +1,118 @@
+package fixtures_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefs(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range fixtures.All() {
+		if f.DotGitHash == "" && f.WorktreeHash == "" {
+			assert.Nil(t, f.Refs())
+
+			continue
+		}
+
+		refs := f.Refs()
+		if f.DotGitHash == "" && refs == nil {
+			// Worktree archives without a .git directory have no references.
+			continue
+		}
+
+		require.NotNil(t, refs, f.Name)
+
+		assert.True(t, slices.IsSortedFunc(refs, func(a, b fixtures.Reference) int {
+			return strings.Compare(a.Name, b.Name)
+		}))
+
+		byName := map[string]fixtures.Reference{}
+		for _, r := range refs {
+			byName[r.Name] = r
+
+			if r.SymbolicTarget != "" {
+				assert.Empty(t, r.Target, r.Name)
+			} else {
+				assert.NotEmpty(t, r.Target, r.Name)
+			}
+		}
+
+		assert.Contains(t, byName, "HEAD")
+	}
+}
+
+func TestRefsBasic(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.Basic().ByTag(".git").One()
+	refs := f.Refs()
+
+	assert.Contains(t, refs, fixtures.Reference{
+		Name:           "HEAD",
+		Target:         "",
+		SymbolicTarget: "refs/heads/master",
+		Peeled:         "",
+		Packed:         false,
+	})
+
+	refs[0].Name = "changed"
+	assert.NotEqual(t, refs[0], f.Refs()[0])
+}
+
+func TestRefsTags(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByTag("tags").One()
+
+	peeled := map[string]string{}
+	for _, r := range f.Refs() {
+		if strings.HasPrefix(r.Name, "refs/tags/") {
+			peeled[r.Name] = r.Peeled
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"refs/tags/annotated-tag":   "f7b877701fbf855b44c0a9e86f3fdce2c298b07f",
+		"refs/tags/blob-tag":        "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391",
+		"refs/tags/commit-tag":      "f7b877701fbf855b44c0a9e86f3fdce2c298b07f",
+		"refs/tags/lightweight-tag": "",
+		"refs/tags/tree-tag":        "70846e9a10ef7b41064b40f07713d5b8b9a8fc73",
+	}, peeled)
+}
+
+func TestRefsHead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag  fixtures.Tag
+		head string
+	}{
+		{tag: "root-reference", head: "refs/heads/1"},
+		{tag: "no-master-head", head: "refs/heads/main"},
+		{tag: "reftable", head: "refs/heads/master"},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.tag), func(t *testing.T) {
+			t.Parallel()
+
+			refs := fixtures.ByTag(tc.tag).One().Refs()
+			require.NotEmpty(t, refs)
+			assert.Equal(t, "HEAD", refs[0].Name)
+			assert.Equal(t, tc.head, refs[0].SymbolicTarget)
+		})
+	}
+}
+
+func TestRefsReturnsNilWithoutDotGit(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByTag("codecommit").One()
+	assert.Nil(t, f.Refs())
+}
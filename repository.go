@@ -0,0 +1,267 @@
+package fixtures
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+)
+
+// ErrNotRepository is returned by Repository for fixtures whose packfile
+// cannot be assembled into a repository.
+var ErrNotRepository = errors.New("fixture cannot be assembled into a repository")
+
+// tipPrefix is the prefix of the branches Repository creates for commits
+// which are not the parent of any other.
+const tipPrefix = "refs/heads/tip-"
+
+// Repository assembles a bare repository around the fixture's packfile,
+// written into a new filesystem created according to opts, so that packfile
+// fixtures without a .git directory can be opened as repositories.
+//
+// The packfile and its idx and rev files are placed in objects/pack. The
+// references are those of the fixture's .git directory, see Refs, if it has
+// one. Otherwise, they are derived from the packfile:
+//
+//   - HEAD points to refs/heads/master, or refs/heads/main for fixtures
+//     tagged TagMainBranch, at Head or, when it is unknown, at the most
+//     recent commit which is not the parent of any other;
+//   - every other commit which is not the parent of any other gets a
+//     refs/heads/tip-<hash> branch, with the first 12 digits of its hash;
+//   - every annotated tag is referenced as refs/tags/<name>.
+//
+// Only references to objects of the packfile are kept, and all of them are
+// written as loose references. Thin packs fail with ErrNotRepository.
+func (f *Fixture) Repository(opts ...Option) (billy.Filesystem, error) {
+	switch {
+	case f.PackfileHash == "":
+		return nil, fmt.Errorf("%w: fixture %s has no packfile", ErrNotRepository, f.runName())
+	case f.Is(TagThinpack):
+		return nil, fmt.Errorf("%w: fixture %s is a thin pack", ErrNotRepository, f.runName())
+	}
+
+	objects, err := f.objects()
+	if err != nil {
+		return nil, err
+	}
+
+	o := newOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	fs, err := o.fsFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range []string{"objects/info", "objects/pack", "refs/heads", "refs/tags"} {
+		err = fs.MkdirAll(dir, 0o755)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = util.WriteFile(fs, "config", []byte(repositoryConfig(f.ObjectFormat, true)), 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.writePackfile(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range f.repositoryRefs(objects) {
+		content := ref.Target
+		if ref.SymbolicTarget != "" {
+			content = "ref: " + ref.SymbolicTarget
+		}
+
+		err = writeRef(fs, ref.Name, content+"\n")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return o.wrap(fs)
+}
+
+// writePackfile copies the packfile, idx and rev files of the fixture into
+// objects/pack.
+func (f *Fixture) writePackfile(fs billy.Filesystem) error {
+	for _, ext := range []string{"pack", "idx", "rev"} {
+		data, err := f.readFile(f.packfilePath(ext))
+		if errors.Is(err, os.ErrNotExist) && ext != "pack" {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// Packs are read-only in repositories written by git.
+		name := fmt.Sprintf("objects/pack/pack-%s.%s", f.PackfileHash, ext)
+
+		err = util.WriteFile(fs, name, data, 0o444)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// repositoryRefs returns the references of the repository assembled by
+// Repository, ordered by name.
+func (f *Fixture) repositoryRefs(objects []Object) []Reference {
+	derived := f.derivedRefs(objects)
+
+	refs := f.Refs()
+	if refs == nil {
+		return derived
+	}
+
+	kept := make([]Reference, 0, len(refs))
+	names := map[string]bool{}
+
+	for _, ref := range refs {
+		if ref.SymbolicTarget == "" && hasObject(objects, ref.Target) {
+			kept = append(kept, ref)
+			names[ref.Name] = true
+		}
+	}
+
+	for _, ref := range refs {
+		if ref.SymbolicTarget != "" && names[ref.SymbolicTarget] {
+			kept = append(kept, ref)
+			names[ref.Name] = true
+		}
+	}
+
+	// HEAD is required for the repository to be valid.
+	if !names["HEAD"] {
+		for _, ref := range derived {
+			if ref.Name == "HEAD" || (ref.Name == derivedBranch(f) && !names[ref.Name]) {
+				kept = append(kept, ref)
+			}
+		}
+	}
+
+	slices.SortFunc(kept, func(a, b Reference) int { return strings.Compare(a.Name, b.Name) })
+
+	return kept
+}
+
+// derivedRefs returns the references derived from the objects of the
+// packfile, as described by Repository, ordered by name.
+func (f *Fixture) derivedRefs(objects []Object) []Reference {
+	branch := derivedBranch(f)
+	commits := f.Commits()
+
+	parents := map[string]bool{}
+
+	for _, c := range commits {
+		for _, p := range c.Parents {
+			parents[p] = true
+		}
+	}
+
+	var tips Commits
+
+	for _, c := range commits {
+		if !parents[c.Hash] {
+			tips = append(tips, c)
+		}
+	}
+
+	head := ""
+	if _, ok := commits.Get(f.Head); ok {
+		head = f.Head
+	} else if len(tips) > 0 {
+		head = slices.MaxFunc(tips, func(a, b Commit) int {
+			if c := a.CommitterDate.Compare(b.CommitterDate); c != 0 {
+				return c
+			}
+
+			return strings.Compare(a.Hash, b.Hash)
+		}).Hash
+	}
+
+	refs := []Reference{{Name: "HEAD", SymbolicTarget: branch}}
+	if head != "" {
+		refs = append(refs, Reference{Name: branch, Target: head})
+	}
+
+	for _, tip := range tips {
+		if tip.Hash != head {
+			refs = append(refs, Reference{Name: tipPrefix + tip.Hash[:12], Target: tip.Hash})
+		}
+	}
+
+	tags := map[string]bool{}
+
+	for _, o := range objects {
+		if o.Type != int(object.TagType) {
+			continue
+		}
+
+		name := tagName(o.content)
+		if name == "" || tags[name] {
+			continue
+		}
+
+		tags[name] = true
+		refs = append(refs, Reference{Name: "refs/tags/" + name, Target: o.Hash})
+	}
+
+	slices.SortFunc(refs, func(a, b Reference) int { return strings.Compare(a.Name, b.Name) })
+
+	return refs
+}
+
+// derivedBranch returns the branch HEAD points to when derived from the
+// packfile.
+func derivedBranch(f *Fixture) string {
+	if f.Is(TagMainBranch) {
+		return "refs/heads/main"
+	}
+
+	return "refs/heads/master"
+}
+
+// tagName returns the name of the annotated tag of the given content, or an
+// empty string if it is not a valid reference name.
+func tagName(content []byte) string {
+	s := bufio.NewScanner(bytes.NewReader(content))
+	for s.Scan() && s.Text() != "" {
+		name, ok := strings.CutPrefix(s.Text(), "tag ")
+		if !ok {
+			continue
+		}
+
+		if checkRefName("refs/tags/"+name) != nil {
+			return ""
+		}
+
+		return name
+	}
+
+	return ""
+}
+
+// hasObject reports whether objects, ordered by hash, has the object hash.
+func hasObject(objects []Object, hash string) bool {
+	_, ok := slices.BinarySearchFunc(objects, hash, func(o Object, h string) int {
+		return strings.Compare(o.Hash, h)
+	})
+
+	return ok
+}
@@ -0,0 +1,166 @@
+package fixtures_test
+
+import (
+	"testing"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/util"
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/go-git/go-git-fixtures/v6/internal/dotgit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range fixtures.All() {
+		if f.PackfileHash == "" || f.Is(fixtures.TagThinpack) || f.Objects() == nil {
+			continue
+		}
+
+		t.Run(f.Name, func(t *testing.T) {
+			t.Parallel()
+
+			fs, err := f.Repository()
+			require.NoError(t, err)
+
+			cfg, err := dotgit.ReadConfig(fs)
+			require.NoError(t, err)
+			assert.Equal(t, f.ObjectFormat, cfg.ObjectFormat)
+
+			_, err = fs.Stat("objects/pack/pack-" + f.PackfileHash + ".idx")
+			require.NoError(t, err)
+
+			pack, err := util.ReadFile(fs, "objects/pack/pack-"+f.PackfileHash+".pack")
+			require.NoError(t, err)
+			assert.Equal(t, readPackfile(t, f), pack)
+
+			head := resolveRefs(t, fs, cfg)
+			if f.Head != "" && f.DotGitHash == "" {
+				assert.Equal(t, f.Head, head)
+			}
+		})
+	}
+}
+
+// resolveRefs checks that every reference of the repository fs points to an
+// object of it, and returns the commit HEAD points to.
+func resolveRefs(t *testing.T, fs billy.Filesystem, cfg dotgit.Config) string {
+	t.Helper()
+
+	refs, err := dotgit.ReadRefs(fs, cfg)
+	require.NoError(t, err)
+
+	byName := map[string]dotgit.Ref{}
+	for _, ref := range refs {
+		byName[ref.Name] = ref
+	}
+
+	store := dotgit.NewObjectStore(fs, cfg.ObjectFormat)
+
+	for _, ref := range refs {
+		if ref.Symbolic != "" {
+			assert.Contains(t, byName, ref.Symbolic, "dangling %s", ref.Name)
+
+			continue
+		}
+
+		_, _, err := store.Get(ref.Target)
+		require.NoError(t, err, ref.Name)
+	}
+
+	head, ok := byName["HEAD"]
+	require.True(t, ok, "no HEAD")
+
+	if head.Symbolic != "" {
+		head = byName[head.Symbolic]
+	}
+
+	return head.Target
+}
+
+func TestRepositoryDerivedRefs(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByName("notes")
+	fs, err := f.Repository()
+	require.NoError(t, err)
+
+	head, err := util.ReadFile(fs, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "ref: refs/heads/master\n", string(head))
+
+	// Every commit which is not the parent of another one is referenced.
+	parents := map[string]bool{}
+	for _, c := range f.Commits() {
+		for _, p := range c.Parents {
+			parents[p] = true
+		}
+	}
+
+	cfg, err := dotgit.ReadConfig(fs)
+	require.NoError(t, err)
+
+	refs, err := dotgit.ReadRefs(fs, cfg)
+	require.NoError(t, err)
+
+	targets := map[string]bool{}
+	for _, ref := range refs {
+		targets[ref.Target] = true
+	}
+
+	for _, c := range f.Commits() {
+		if !parents[c.Hash] {
+			assert.True(t, targets[c.Hash], "commit %s not referenced", c.Hash)
+		}
+	}
+}
+
+func TestRepositoryKeepsRefs(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByName("tags")
+	fs, err := f.Repository()
+	require.NoError(t, err)
+
+	cfg, err := dotgit.ReadConfig(fs)
+	require.NoError(t, err)
+
+	refs, err := dotgit.ReadRefs(fs, cfg)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+
+	for _, ref := range f.Refs() {
+		assert.Contains(t, names, ref.Name)
+	}
+}
+
+func TestRepositoryOptions(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByName("spinnaker")
+
+	fs, err := f.Repository(fixtures.WithTargetDir(t.TempDir), fixtures.WithReadOnly())
+	require.NoError(t, err)
+	assert.NotEmpty(t, fs.Root())
+
+	_, err = fs.Stat("HEAD")
+	require.NoError(t, err)
+
+	_, err = fs.Create("refs/heads/new")
+	require.Error(t, err)
+}
+
+func TestRepositoryNotApplicable(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"thinpack", "basic-worktree"} {
+		_, err := fixtures.ByName(name).Repository()
+		require.ErrorIs(t, err, fixtures.ErrNotRepository, name)
+	}
+}
@@ -0,0 +1,115 @@
+package fixtures
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-git/go-billy/v6"
+)
+
+// Extract is the content of a fixture extracted by RunWith.
+type Extract int
+
+const (
+	// ExtractNone does not extract anything, and passes a nil filesystem.
+	ExtractNone Extract = iota
+	// ExtractDotGit extracts the .git directory, as returned by DotGit.
+	ExtractDotGit
+	// ExtractWorktree extracts the worktree, as returned by Worktree.
+	ExtractWorktree
+)
+
+// RunOptions configures how RunWith runs each fixture.
+type RunOptions struct {
+	// Parallel marks each subtest as parallel. It only applies to tests.
+	Parallel bool
+	// Extract is what to extract before calling the test function.
+	Extract Extract
+	// FS are the options used to extract the fixture, e.g. WithTargetDir.
+	FS []Option
+	// TempDir extracts the fixture into a temporary directory of the
+	// subtest, removed when it completes. It takes precedence over any
+	// target dir in FS.
+	TempDir bool
+	// Skip, if set, skips the fixtures for which it returns true.
+	Skip func(*Fixture) bool
+}
+
+// RunWith calls test within a subtest or sub-benchmark for each fixture in
+// g, named as in Run, along with the fixture content selected by
+// opts.Extract.
+//
+// For benchmarks, the timer is reset once the fixture is extracted. If tb is
+// neither a *testing.T nor a *testing.B, fixtures are run in sequence without
+// subtests.
+func (g Fixtures) RunWith(tb testing.TB, opts RunOptions, test func(testing.TB, *Fixture, billy.Filesystem)) {
+	tb.Helper()
+
+	for _, f := range g {
+		switch tb := tb.(type) {
+		case *testing.T:
+			tb.Run(f.runName(), func(t *testing.T) {
+				t.Helper()
+
+				if opts.Parallel {
+					t.Parallel()
+				}
+
+				opts.run(t, f, test)
+			})
+		case *testing.B:
+			tb.Run(f.runName(), func(b *testing.B) {
+				b.Helper()
+				opts.run(b, f, test)
+			})
+		default:
+			opts.run(tb, f, test)
+		}
+	}
+}
+
+func (o RunOptions) run(tb testing.TB, f *Fixture, test func(testing.TB, *Fixture, billy.Filesystem)) {
+	tb.Helper()
+
+	if o.Skip != nil && o.Skip(f) {
+		tb.Skipf("fixture %s skipped", f.runName())
+	}
+
+	fs, err := o.extract(tb, f)
+	if err != nil {
+		tb.Fatalf("extracting fixture %s: %v", f.runName(), err)
+	}
+
+	if b, ok := tb.(*testing.B); ok {
+		b.ResetTimer()
+	}
+
+	test(tb, f, fs)
+}
+
+func (o RunOptions) extract(tb testing.TB, f *Fixture) (billy.Filesystem, error) {
+	opts := o.FS
+	if o.TempDir {
+		opts = append(opts[:len(opts):len(opts)], WithTargetDir(tb.TempDir))
+	}
+
+	switch o.Extract {
+	case ExtractNone:
+		return nil, nil //nolint:nilnil // nothing to extract.
+	case ExtractDotGit:
+		return f.DotGit(opts...)
+	case ExtractWorktree:
+		return f.Worktree(opts...)
+	default:
+		return nil, fmt.Errorf("unknown extract mode %d", o.Extract)
+	}
+}
+
+// runName returns the name of the subtests running f.
+func (f *Fixture) runName() string {
+	if f.Name == "" {
+		return fmt.Sprintf("fixture run (%q, %q)", f.URL, f.Tags)
+	}
+
+	return f.Name
+}
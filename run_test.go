@@ -0,0 +1,106 @@
+package fixtures_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/osfs"
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWith(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		names []string
+	)
+
+	t.Run("parallel", func(t *testing.T) { //nolint:paralleltest // waits for its parallel subtests.
+		fixtures.Basic().ByTag(fixtures.TagDotGit).RunWith(t, fixtures.RunOptions{
+			Parallel: true,
+			Extract:  fixtures.ExtractDotGit,
+			Skip:     func(f *fixtures.Fixture) bool { return f.ObjectFormat == "sha256" },
+		}, func(tb testing.TB, f *fixtures.Fixture, fs billy.Filesystem) {
+			assert.NotEqual(tb, "sha256", f.ObjectFormat)
+
+			_, err := fs.Stat("HEAD")
+			require.NoError(tb, err)
+
+			mu.Lock()
+			names = append(names, tb.Name())
+			mu.Unlock()
+		})
+	})
+
+	slices.Sort(names)
+	assert.Equal(t, []string{
+		"TestRunWith/parallel/basic-eoie",
+		"TestRunWith/parallel/basic-index-v3",
+		"TestRunWith/parallel/basic-index-v4",
+		"TestRunWith/parallel/basic-merge-conflict",
+		"TestRunWith/parallel/basic-ofs-delta",
+		"TestRunWith/parallel/basic-ref-delta",
+		"TestRunWith/parallel/basic-reftable",
+		"TestRunWith/parallel/basic-resolve-undo",
+	}, names)
+}
+
+func TestRunWithTempDir(t *testing.T) {
+	t.Parallel()
+
+	var roots []string
+
+	fixtures.ByTag(fixtures.TagSubmodule).RunWith(t, fixtures.RunOptions{
+		Extract: fixtures.ExtractWorktree,
+		TempDir: true,
+	}, func(tb testing.TB, f *fixtures.Fixture, fs billy.Filesystem) {
+		bfs, ok := fs.(*osfs.BoundOS)
+		require.True(tb, ok, "not an OS filesystem")
+
+		_, err := fs.Stat(".git")
+		require.NoError(tb, err)
+
+		roots = append(roots, bfs.Root())
+	})
+
+	require.Len(t, roots, 2)
+	assert.NotEqual(t, roots[0], roots[1])
+
+	for _, root := range roots {
+		assert.NoDirExists(t, root)
+	}
+}
+
+func TestRunWithNone(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	fixtures.ByTag(fixtures.TagPackfile).RunWith(t, fixtures.RunOptions{}, func(tb testing.TB, f *fixtures.Fixture, fs billy.Filesystem) {
+		assert.Nil(tb, fs)
+		assert.NotEmpty(tb, f.PackfileHash)
+
+		calls++
+	})
+
+	assert.Equal(t, len(fixtures.ByTag(fixtures.TagPackfile)), calls)
+}
+
+func BenchmarkRunWith(b *testing.B) {
+	fixtures.Basic().ByTag(fixtures.TagPackfile).RunWith(b, fixtures.RunOptions{
+		Extract: fixtures.ExtractDotGit,
+	}, func(tb testing.TB, _ *fixtures.Fixture, fs billy.Filesystem) {
+		b, ok := tb.(*testing.B)
+		require.True(tb, ok)
+
+		for b.Loop() {
+			_, err := fs.Stat("HEAD")
+			require.NoError(b, err)
+		}
+	})
+}
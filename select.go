@@ -0,0 +1,331 @@
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidQuery = errors.New("invalid query")
+	ErrUnknownTag   = errors.New("unknown tag")
+)
+
+// Select returns the fixtures matching query. See Fixtures.Select for the
+// query syntax.
+func Select(query string) (Fixtures, error) {
+	return fixtures.Select(query)
+}
+
+// Select returns the fixtures of g matching query, a boolean expression over
+// fixture tags:
+//
+//	packfile && (ofs-delta || ref-delta) && !thinpack && format==sha256
+//
+// A bare word matches fixtures with that tag, and must be listed by Tags, so
+// that typos are reported rather than silently matching nothing. The fields
+// format, size (see SizeClass) and url can be compared with == and !=,
+// against a bare word or a double-quoted string. ! binds tighter than &&,
+// which binds tighter than ||.
+func (g Fixtures) Select(query string) (Fixtures, error) {
+	match, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make(Fixtures, 0, len(g))
+	for _, f := range g {
+		if match(f) {
+			r = append(r, f.Clone())
+		}
+	}
+
+	return r, nil
+}
+
+type matcher func(*Fixture) bool
+
+type queryField struct {
+	get func(*Fixture) string
+	// values are the valid values of the field, or nil if any is.
+	values []string
+}
+
+//nolint:gochecknoglobals
+var queryFields = map[string]queryField{
+	"format": {
+		get:    func(f *Fixture) string { return f.ObjectFormat },
+		values: []string{objectFormatSHA1, objectFormatSHA256},
+	},
+	"size": {
+		get:    func(f *Fixture) string { return f.Size().String() },
+		values: []string{SizeUnknown.String(), SizeSmall.String(), SizeMedium.String(), SizeLarge.String()},
+	},
+	"url": {
+		get:    func(f *Fixture) string { return f.URL },
+		values: nil,
+	},
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenEqual
+	tokenNotEqual
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of query"
+	}
+
+	return fmt.Sprintf("%q at offset %d", t.value, t.pos)
+}
+
+//nolint:gochecknoglobals
+var operators = []struct {
+	text string
+	kind tokenKind
+}{
+	{"&&", tokenAnd},
+	{"||", tokenOr},
+	{"==", tokenEqual},
+	{"!=", tokenNotEqual},
+	{"!", tokenNot},
+	{"(", tokenLParen},
+	{")", tokenRParen},
+}
+
+// tokenize splits query into tokens, ending with a tokenEOF one.
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(query); {
+		if isSpace(query[i]) {
+			i++
+
+			continue
+		}
+
+		if tok, ok := operatorAt(query, i); ok {
+			tokens = append(tokens, tok)
+			i += len(tok.value)
+
+			continue
+		}
+
+		if query[i] == '"' {
+			end := strings.IndexByte(query[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated string at offset %d", ErrInvalidQuery, i)
+			}
+
+			tokens = append(tokens, token{kind: tokenString, value: query[i+1 : i+1+end], pos: i})
+			i += end + 2
+
+			continue
+		}
+
+		start := i
+		for i < len(query) && !isSpace(query[i]) && query[i] != '"' && !isOperatorAt(query, i) {
+			i++
+		}
+
+		tokens = append(tokens, token{kind: tokenWord, value: query[start:i], pos: start})
+	}
+
+	return append(tokens, token{kind: tokenEOF, value: "", pos: len(query)}), nil
+}
+
+func operatorAt(query string, i int) (token, bool) {
+	for _, op := range operators {
+		if strings.HasPrefix(query[i:], op.text) {
+			return token{kind: op.kind, value: op.text, pos: i}, true
+		}
+	}
+
+	return token{}, false
+}
+
+func isOperatorAt(query string, i int) bool {
+	_, ok := operatorAt(query, i)
+
+	return ok
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// queryParser is a recursive descent parser for the grammar:
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | primary
+//	primary = "(" or ")" | word [ ( "==" | "!=" ) ( word | string ) ]
+type queryParser struct {
+	tokens []token
+	pos    int
+}
+
+func parseQuery(query string) (matcher, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{tokens: tokens, pos: 0}
+
+	match, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("%w: unexpected %s", ErrInvalidQuery, tok)
+	}
+
+	return match, nil
+}
+
+func (p *queryParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *queryParser) or() (matcher, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(f *Fixture) bool { return l(f) || right(f) }
+	}
+
+	return left, nil
+}
+
+func (p *queryParser) and() (matcher, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd {
+		p.next()
+
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(f *Fixture) bool { return l(f) && right(f) }
+	}
+
+	return left, nil
+}
+
+func (p *queryParser) unary() (matcher, error) {
+	if p.peek().kind != tokenNot {
+		return p.primary()
+	}
+
+	p.next()
+
+	match, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(f *Fixture) bool { return !match(f) }, nil
+}
+
+func (p *queryParser) primary() (matcher, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenLParen:
+		match, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		if end := p.next(); end.kind != tokenRParen {
+			return nil, fmt.Errorf("%w: expected \")\", got %s", ErrInvalidQuery, end)
+		}
+
+		return match, nil
+	case tokenWord:
+		if kind := p.peek().kind; kind == tokenEqual || kind == tokenNotEqual {
+			return p.comparison(tok)
+		}
+
+		tag := Tag(tok.value)
+		if !isKnownTag(tag) {
+			return nil, fmt.Errorf("%w: %q at offset %d", ErrUnknownTag, tok.value, tok.pos)
+		}
+
+		return func(f *Fixture) bool { return f.Is(tag) }, nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected %s", ErrInvalidQuery, tok)
+	}
+}
+
+func (p *queryParser) comparison(field token) (matcher, error) {
+	qf, ok := queryFields[field.value]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown field %s", ErrInvalidQuery, field)
+	}
+
+	op := p.next()
+
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, fmt.Errorf("%w: expected a value after %s, got %s", ErrInvalidQuery, op, value)
+	}
+
+	if qf.values != nil && !slices.Contains(qf.values, value.value) {
+		return nil, fmt.Errorf("%w: invalid %s value %s", ErrInvalidQuery, field.value, value)
+	}
+
+	get := qf.get
+
+	if op.kind == tokenNotEqual {
+		return func(f *Fixture) bool { return get(f) != value.value }, nil
+	}
+
+	return func(f *Fixture) bool { return get(f) == value.value }, nil
+}
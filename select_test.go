@@ -0,0 +1,110 @@
+package fixtures_test
+
+import (
+	"testing"
+
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query string
+		want  func(*fixtures.Fixture) bool
+	}{
+		{
+			query: "packfile",
+			want:  func(f *fixtures.Fixture) bool { return f.Is("packfile") },
+		},
+		{
+			query: "packfile && (ofs-delta || ref-delta) && !thinpack",
+			want: func(f *fixtures.Fixture) bool {
+				return f.Is("packfile") && (f.Is("ofs-delta") || f.Is("ref-delta")) && !f.Is("thinpack")
+			},
+		},
+		{
+			query: "packfile || .git && format==sha256",
+			want: func(f *fixtures.Fixture) bool {
+				return f.Is("packfile") || (f.Is(".git") && f.ObjectFormat == "sha256")
+			},
+		},
+		{
+			query: "!!worktree && format != sha1",
+			want: func(f *fixtures.Fixture) bool {
+				return f.Is("worktree") && f.ObjectFormat != "sha1"
+			},
+		},
+		{
+			query: `url=="https://github.com/git-fixtures/basic.git" && !single-branch`,
+			want: func(f *fixtures.Fixture) bool {
+				return f.URL == "https://github.com/git-fixtures/basic.git" && !f.Is("single-branch")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := fixtures.Select(tc.query)
+			require.NoError(t, err)
+			require.NotEmpty(t, got)
+
+			var want fixtures.Fixtures
+
+			for _, f := range fixtures.All() {
+				if tc.want(f) {
+					want = append(want, f)
+				}
+			}
+
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestSelectNarrowing(t *testing.T) {
+	t.Parallel()
+
+	got, err := fixtures.Basic().Select("ofs-delta")
+	require.NoError(t, err)
+	assert.Equal(t, fixtures.Basic().ByTag("ofs-delta"), got)
+
+	got, err = fixtures.Basic().Select("format==sha256")
+	require.NoError(t, err)
+	assert.Equal(t, fixtures.Basic().ByObjectFormat("sha256"), got)
+}
+
+func TestSelectErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query string
+		want  error
+	}{
+		{query: "packfiel", want: fixtures.ErrUnknownTag},
+		{query: "packfile && !ofs-detla", want: fixtures.ErrUnknownTag},
+		{query: "", want: fixtures.ErrInvalidQuery},
+		{query: "packfile &&", want: fixtures.ErrInvalidQuery},
+		{query: "packfile ofs-delta", want: fixtures.ErrInvalidQuery},
+		{query: "(packfile", want: fixtures.ErrInvalidQuery},
+		{query: "packfile)", want: fixtures.ErrInvalidQuery},
+		{query: "format==sha265", want: fixtures.ErrInvalidQuery},
+		{query: "size==big", want: fixtures.ErrInvalidQuery},
+		{query: `url=="unterminated`, want: fixtures.ErrInvalidQuery},
+		{query: "format==", want: fixtures.ErrInvalidQuery},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := fixtures.Select(tc.query)
+			require.ErrorIs(t, err, tc.want)
+			assert.Nil(t, got)
+		})
+	}
+}
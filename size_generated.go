@@ -0,0 +1,62 @@
+// Code generated by internal/cmd/genmeta. DO NOT EDIT.
+
+package fixtures
+
+//nolint:gochecknoglobals
+var fixtureObjectBytes = map[string]int64{
+	"git-00a1fc100787506f842e55511994f08df2c2cd66":                              165,
+	"git-21504f6d2cc2ef0c9d6ebb8802c7b49abae40c1a":                              313672,
+	"git-26baa505b9f6fb2024b9999c140b75514718c988":                              9113,
+	"git-40143428b59fe03546fabba0603268bba3b3c58b":                              319609,
+	"git-4870d54b5b04e43da8cf99ceec179d9675494af8":                              336926,
+	"git-4e7600af05c3356e8b142263e127b76f010facfc":                              336926,
+	"git-5f620e4b3194c0c4a77fbd17f501030a441f54d4":                              314207,
+	"git-77b6511a6e67c99162ebcecd2763a9a19a7ad429":                              0,
+	"git-78c5fb882e76286d8201016cffee63ea7060a0c2":                              318630,
+	"git-7a725350b88b05ca03541b59dd0649fda7f521f2":                              314207,
+	"git-7cbde0ca02f13aedd5ec8b358ca17b1c0bf5ee64":                              314207,
+	"git-7cdeabb9642835c4f003aa38e6cfbe8c316d2e89":                              2994,
+	"git-827d08501b81099c1db24f4b309b90df149ab0a2":                              3354,
+	"git-935e5ac17c41c309c356639816ea0694a568c484":                              336926,
+	"git-9796b2f5d699a996e418ce3d43e14f819ce39133":                              2994,
+	"git-ab06771a67110b976953d34400d4dbc465ccd2d9":                              314207,
+	"git-bf3fedcc8e20fd0dec9172987ceea0038d17b516":                              0,
+	"git-c0c7c57ab1753ddbd26cc45322299ddd12842794":                              821,
+	"git-c20badf43d2495f93b42cb3ea98ed04651510617da9b56d4e07c5837ec08f93d":      316389,
+	"git-cf717ccadce761d60bb4a8557a7b9a2efd23816a":                              165,
+	"git-df6781fd40b8f4911d70ce71f8387b991615cd6d":                              336926,
+	"git-e1580a78f7d36791249df76df8a2a2613d629902":                              180,
+	"git-f5b4ce165630126595cfea921c50a7b4f932f4f8":                              3354,
+	"pack-06ede69e9eba9f1af36eeee184402dc3ad705cd7":                             259036,
+	"pack-0d3d824fb5c930e7e7e1f0f399f2976847d31fd3":                             1868242,
+	"pack-0d9b6cfc261785837939aaede5986d7a7c212518":                             721278,
+	"pack-135fe3d1ad828afe68706f1d481aedbcfa7a86d2":                             318630,
+	"pack-1ea0b3971fd64fdcdf3282bfb58e8cf10095e4e6":                             87764,
+	"pack-21b33a26eb7ffbd35261149fe5d886b9debab7cb":                             75671,
+	"pack-29f304662fd64f102d94722cf5bd8802d9a9472c":                             180,
+	"pack-3638209d310e10ea8d90c362d568be65dd5e03a6":                             6927,
+	"pack-36ef7a2296bfd526020340d27c5e1faa805d8d38":                             184106,
+	"pack-407497645643e18a7ba56c6132603f167fe9c51c00361ee0c81d74a8f55d0ee2":     1451,
+	"pack-4ec6344877f494690fc800aceaf2ca0e86786acb":                             1091823,
+	"pack-61f0ee9c75af1f9678e6f76ff39fbe372b6f1c45":                             313672,
+	"pack-769137af7784db501bca677fbd56fef8b52515b7":                             3928,
+	"pack-7861f2632868833a35fe5e4ab94f99638ec5129b":                             11332260,
+	"pack-90fedc00729b64ea0d0406db861be081cda25bbf":                             45098,
+	"pack-9733763ae7ee6efcf452d373d6fff77424fb1dcc":                             119897,
+	"pack-a3fed42da1e8189a077c0e6846c040dcf73fc9dd":                             314207,
+	"pack-b68617dd8637fe6409d9842825a843a1d9a6e484":                             821,
+	"pack-bb8ee94710d3fa39379a630f76812c187217b312":                             5076,
+	"pack-bc4b855a55cae7703c023d4e36e3a7c9f5d84491":                             627,
+	"pack-c544593473465e6315ad4182d04d366c4592b829":                             314207,
+	"pack-c88dfe1663bd216e278d5bb3c8decd0a4bb174a6204585dc44b7c7a05fceed55":     316389,
+	"pack-ee4fef0ef8be5053ebae4ce75acf062ddf3031fb":                             5211,
+	"pack-f2e0a8889a746f7600e07d2246a2e29a72f696be":                             9810741,
+	"worktree-363d996b02d9c3b598f0176619f5c6a44a82480a":                         1214,
+	"worktree-7203669c66103305e56b9dcdf940a7fbeb515f28":                         321,
+	"worktree-8b4d55c85677b6b94bef2e46832ed2174ed6ecaf":                         1592,
+	"worktree-8be1f59f7aa3fc70ea4ff9f8fdb49b2c977222eb":                         643,
+	"worktree-a6b6ff89c593f042347113203ead1c14ab5733ce":                         157,
+	"worktree-d2e42ddd68eacbb6034e7724e0dd4117ff1f01ee":                         314207,
+	"worktree-df8b2731b8978b5efca95cc2eef5ee43915e0945e3310653b0ef0c00453d67c4": 4644,
+	"worktree-e3b91f99d8d050cac81d84fbef89172f58eeb745":                         1078,
+}
@@ -0,0 +1,210 @@
+package fixtures
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Tag is a tag of fixtures, such as TagPackfile.
+type Tag string
+
+// Tags of the fixtures, to be used with ByTag, Exclude and Select. See Tags
+// for what each of them guarantees.
+const (
+	TagAlternates        Tag = "alternates"
+	TagCodeCommit        Tag = "codecommit"
+	TagCommitGraph       Tag = "commit-graph"
+	TagCommitGraphChain  Tag = "commit-graph-chain"
+	TagCommitGraphChain2 Tag = "commit-graph-chain-2"
+	TagDeltaBeforeBase   Tag = "delta-before-base"
+	TagDiffTree          Tag = "diff-tree"
+	TagDirty             Tag = "dirty"
+	TagDotGit            Tag = ".git"
+	TagEmpty             Tag = "empty"
+	TagEmptyFolder       Tag = "empty-folder"
+	TagEndOfIndexEntry   Tag = "end-of-index-entry"
+	TagExecutable        Tag = "executable"
+	TagGenerated         Tag = "generated"
+	TagHardlinks         Tag = "hardlinks"
+	TagIDXV2             Tag = "idx-v2"
+	TagIndexExtEOIE      Tag = "index-ext-eoie"
+	TagIndexExtNone      Tag = "index-ext-none"
+	TagIndexExtREUC      Tag = "index-ext-reuc"
+	TagIndexExtTree      Tag = "index-ext-tree"
+	TagIndexV2           Tag = "index-v2"
+	TagIndexV3           Tag = "index-v3"
+	TagIndexV4           Tag = "index-v4"
+	TagIntentToAdd       Tag = "intent-to-add"
+	TagLinkedWorktree    Tag = "linked-worktree"
+	TagMainBranch        Tag = "main-branch"
+	TagMergeBase         Tag = "merge-base"
+	TagMergeConflict     Tag = "merge-conflict"
+	TagMIDXBitmap        Tag = "midx-bitmap"
+	TagMIDXRIDX          Tag = "midx-ridx"
+	TagMIDXV1            Tag = "midx-v1"
+	TagMultiPackIndex    Tag = "multi-pack-index"
+	TagMultiPackfile     Tag = "multi-packfile"
+	TagNoMasterHead      Tag = "no-master-head"
+	TagNotes             Tag = "notes"
+	TagOFSDelta          Tag = "ofs-delta"
+	TagPackV2            Tag = "pack-v2"
+	TagPackfile          Tag = "packfile"
+	TagPackfileEntries   Tag = "packfile-entries"
+	TagREFDelta          Tag = "ref-delta"
+	TagReftable          Tag = "reftable"
+	TagResolveUndo       Tag = "resolve-undo"
+	TagRevV1             Tag = "rev-v1"
+	TagRootReference     Tag = "root-reference"
+	TagScannerEntries    Tag = "scanner-entries"
+	TagSingleBranch      Tag = "single-branch"
+	TagStandalone        Tag = "standalone"
+	TagSubmodule         Tag = "submodule"
+	TagSymlinks          Tag = "symlinks"
+	TagTags              Tag = "tags"
+	TagThinpack          Tag = "thinpack"
+	TagUnpacked          Tag = "unpacked"
+	TagWorktree          Tag = "worktree"
+)
+
+// StrictTagsEnv is the environment variable enabling strict tags when set to
+// a true value, as parsed by strconv.ParseBool. See SetStrictTags.
+const StrictTagsEnv = "GO_GIT_FIXTURES_STRICT_TAGS"
+
+// TagInfo describes a fixture tag.
+type TagInfo struct {
+	// Name is the tag, e.g. TagPackfile.
+	Name Tag
+	// Description is what fixtures with the tag provide.
+	Description string
+}
+
+// tagInfos lists the tags ordered by name, as isKnownTag binary searches it.
+//
+//nolint:gochecknoglobals
+var tagInfos = []TagInfo{
+	{Name: TagDotGit, Description: "Has a .git directory, returned by DotGit."},
+	{Name: TagAlternates, Description: "The worktree holds repositories sharing objects through objects/info/alternates."},
+	{Name: TagCodeCommit, Description: "The packfile was served by AWS CodeCommit."},
+	{Name: TagCommitGraph, Description: "The .git directory has a commit-graph file."},
+	{Name: TagCommitGraphChain, Description: "The .git directory has a chain of commit-graph files."},
+	{Name: TagCommitGraphChain2, Description: "Another .git directory with a chain of commit-graph files."},
+	{Name: TagDeltaBeforeBase, Description: "The packfile has deltas preceding their base object."},
+	{Name: TagDiffTree, Description: "The packfile history is used by tree diff tests."},
+	{Name: TagDirty, Description: "The worktree has changes which are not committed."},
+	{Name: TagEmpty, Description: "The repository has no commits."},
+	{Name: TagEmptyFolder, Description: "The history has trees with empty folders."},
+	{Name: TagEndOfIndexEntry, Description: "Alias of index-ext-eoie."},
+	{Name: TagExecutable, Description: "The worktree has files with the executable bit set."},
+	{Name: TagGenerated, Description: "The fixture was synthesized by a Builder rather than stored under data/."},
+	{Name: TagHardlinks, Description: "The worktree has hard links."},
+	{Name: TagIDXV2, Description: "The packfile has a version 2 idx file, returned by Idx."},
+	{Name: TagIndexExtEOIE, Description: "The index has an end of index entries (EOIE) extension."},
+	{Name: TagIndexExtNone, Description: "The index has no extensions."},
+	{Name: TagIndexExtREUC, Description: "The index has a resolve undo (REUC) extension."},
+	{Name: TagIndexExtTree, Description: "The index has a cached tree (TREE) extension."},
+	{Name: TagIndexV2, Description: "The index is in version 2 format."},
+	{Name: TagIndexV3, Description: "The index is in version 3 format."},
+	{Name: TagIndexV4, Description: "The index is in version 4 format."},
+	{Name: TagIntentToAdd, Description: "The index has entries added with git add -N."},
+	{Name: TagLinkedWorktree, Description: "The worktree holds a repository and its linked worktrees."},
+	{Name: TagMainBranch, Description: "The default branch is main."},
+	{Name: TagMergeBase, Description: "The history is used by merge base tests, with a tag per commit."},
+	{Name: TagMergeConflict, Description: "The index has unresolved merge conflicts."},
+	{
+		Name: TagMIDXBitmap,
+		Description: "The multi-pack-index has a preferred pack and a reachability bitmap, named " +
+			"after and holding its checksum.",
+	},
+	{Name: TagMIDXRIDX, Description: "The multi-pack-index has a reverse index (RIDX) chunk."},
+	{Name: TagMIDXV1, Description: "The multi-pack-index is in version 1 format."},
+	{
+		Name:        TagMultiPackIndex,
+		Description: "The .git directory has a multi-pack-index, described by MultiPackIndex.",
+	},
+	{Name: TagMultiPackfile, Description: "The .git directory has several packfiles."},
+	{Name: TagNoMasterHead, Description: "The repository has no master branch."},
+	{Name: TagNotes, Description: "The packfile has git notes."},
+	{Name: TagOFSDelta, Description: "The packfile stores deltas as offset deltas."},
+	{Name: TagPackV2, Description: "The packfile is in version 2 format."},
+	{Name: TagPackfile, Description: "Has a packfile, returned by Packfile."},
+	{Name: TagPackfileEntries, Description: "Entries returns the offset of every object of the packfile, from its idx file."},
+	{Name: TagREFDelta, Description: "The packfile stores deltas as reference deltas."},
+	{Name: TagReftable, Description: "The .git directory stores references in the reftable format."},
+	{Name: TagResolveUndo, Description: "The index records merge conflicts which were resolved."},
+	{Name: TagRevV1, Description: "The packfile has a version 1 rev file, returned by Rev."},
+	{Name: TagRootReference, Description: "The history has several root commits."},
+	{Name: TagScannerEntries, Description: "ScannerEntries returns every entry of the packfile, in stream order."},
+	{Name: TagSingleBranch, Description: "The repository was cloned with a single branch."},
+	{
+		Name:        TagStandalone,
+		Description: "The packfile has no dependencies and is not part of the repository of another fixture.",
+	},
+	{Name: TagSubmodule, Description: "The worktree has submodules."},
+	{Name: TagSymlinks, Description: "The worktree has symbolic links."},
+	{Name: TagTags, Description: "The repository has lightweight and annotated tags."},
+	{
+		Name:        TagThinpack,
+		Description: "The packfile is a thin pack, with deltas against objects it does not hold, and has no idx or rev file.",
+	},
+	{Name: TagUnpacked, Description: "The .git directory has loose objects."},
+	{Name: TagWorktree, Description: "Has a worktree, returned by Worktree."},
+}
+
+// Tags returns the tags of the fixtures, ordered by name, along with what
+// fixtures with each tag provide.
+func Tags() []TagInfo {
+	return slices.Clone(tagInfos)
+}
+
+//nolint:gochecknoglobals
+var (
+	strictTags        atomic.Bool
+	loadStrictTagsEnv = sync.OnceFunc(func() {
+		strict, _ := strconv.ParseBool(os.Getenv(StrictTagsEnv))
+		strictTags.Store(strict)
+	})
+)
+
+// SetStrictTags sets whether querying fixtures by a tag which is not listed
+// by Tags panics, with an error wrapping ErrUnknownTag, so that the test
+// making the query fails. It applies to ByTag, Exclude and Fixture.Is, which
+// otherwise match no fixture for such tags, and overrides StrictTagsEnv.
+// Select always reports unknown tags, as ErrUnknownTag.
+//
+// As it applies to the whole process, it is meant to be called from TestMain,
+// before any test runs.
+func SetStrictTags(strict bool) {
+	loadStrictTagsEnv()
+	strictTags.Store(strict)
+}
+
+// isKnownTag reports whether tag is listed by Tags.
+func isKnownTag(tag Tag) bool {
+	_, ok := slices.BinarySearchFunc(tagInfos, tag, func(t TagInfo, name Tag) int {
+		return strings.Compare(string(t.Name), string(name))
+	})
+
+	return ok
+}
+
+// checkTag panics if strict tags are enabled and tag is unknown.
+func checkTag(tag Tag) {
+	loadStrictTagsEnv()
+
+	if strictTags.Load() {
+		mustBeKnownTag(tag)
+	}
+}
+
+// mustBeKnownTag panics with an error wrapping ErrUnknownTag if tag is not
+// listed by Tags.
+func mustBeKnownTag(tag Tag) {
+	if !isKnownTag(tag) {
+		panic(fmt.Errorf("%w: %q", ErrUnknownTag, tag))
+	}
+}
@@ -0,0 +1,22 @@
+package fixtures
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagInfosSorted(t *testing.T) {
+	t.Parallel()
+
+	// isKnownTag binary searches tagInfos.
+	assert.True(t, slices.IsSortedFunc(tagInfos, func(a, b TagInfo) int {
+		return strings.Compare(string(a.Name), string(b.Name))
+	}), "tagInfos is not sorted by name")
+
+	for _, info := range tagInfos {
+		assert.True(t, isKnownTag(info.Name), info.Name)
+	}
+}
@@ -0,0 +1,78 @@
+package fixtures_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	t.Parallel()
+
+	tags := fixtures.Tags()
+	require.NotEmpty(t, tags)
+
+	assert.True(t, slices.IsSortedFunc(tags, func(a, b fixtures.TagInfo) int {
+		return strings.Compare(string(a.Name), string(b.Name))
+	}), "tags are not sorted")
+
+	names := make([]fixtures.Tag, 0, len(tags))
+	for _, tag := range tags {
+		assert.NotEmpty(t, tag.Description, tag.Name)
+		names = append(names, tag.Name)
+	}
+
+	used := map[fixtures.Tag]bool{}
+
+	for _, f := range fixtures.All() {
+		for _, tag := range f.Tags {
+			assert.Contains(t, names, tag, "tag of %s is not listed", f.URL)
+			used[tag] = true
+		}
+	}
+
+	b := newHistoryBuilder(t, "sha1", 2)
+
+	for _, opts := range [][]fixtures.PackOption{
+		{fixtures.WithDelta(fixtures.DeltaOFS)},
+		{fixtures.WithDelta(fixtures.DeltaREF), fixtures.WithDeltaBeforeBase()},
+	} {
+		f, err := b.Packfile(opts...)
+		require.NoError(t, err)
+
+		for _, tag := range f.Tags {
+			assert.Contains(t, names, tag, "tag of generated fixture is not listed")
+			used[tag] = true
+		}
+	}
+
+	for _, name := range names {
+		assert.True(t, used[name], "tag %q is not used by any fixture", name)
+	}
+}
+
+//nolint:paralleltest // strict tags are global, see SetStrictTags.
+func TestStrictTags(t *testing.T) {
+	t.Cleanup(func() { fixtures.SetStrictTags(false) })
+
+	fixtures.SetStrictTags(false)
+	assert.Empty(t, fixtures.ByTag("pakfile"))
+
+	fixtures.SetStrictTags(true)
+
+	assert.NotEmpty(t, fixtures.ByTag(fixtures.TagPackfile))
+	assert.NotEmpty(t, fixtures.All().Exclude(fixtures.TagThinpack))
+	assert.True(t, fixtures.Basic().One().Is(fixtures.TagPackfile))
+
+	for name, query := range map[string]func(){
+		"ByTag":   func() { fixtures.ByTag("pakfile") },
+		"Exclude": func() { fixtures.Fixtures{}.Exclude("pakfile") },
+		"Is":      func() { fixtures.Basic().One().Is("pakfile") },
+	} {
+		assert.PanicsWithError(t, `unknown tag: "pakfile"`, query, name)
+	}
+}
@@ -0,0 +1,292 @@
+package fixtures
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-git/go-git-fixtures/v6/internal/idxfile"
+	"github.com/go-git/go-git-fixtures/v6/internal/object"
+	"github.com/go-git/go-git-fixtures/v6/internal/packfile"
+	"github.com/go-git/go-git-fixtures/v6/internal/revfile"
+)
+
+var (
+	ErrChecksumMismatch     = errors.New("checksum mismatch")
+	ErrObjectsCountMismatch = errors.New("objects count mismatch")
+	ErrContentMismatch      = errors.New("content mismatch")
+)
+
+// knownMismatch is a mismatch between a published fixture and its data,
+// kept so that code relying on the fixture's hashes and counts keeps working.
+// Only the exact declared and actual values are accepted.
+type knownMismatch struct {
+	// worktreeHash is the WorktreeHash of the fixture, and archiveDigest the
+	// actual digest of its archive.
+	worktreeHash, archiveDigest string
+	// objectsCount is the ObjectsCount of the fixture, and packObjects the
+	// actual number of objects of its packfile.
+	objectsCount, packObjects int32
+}
+
+// knownMismatches are the known mismatches, by fixture name.
+//
+//nolint:gochecknoglobals
+var knownMismatches = map[string]knownMismatch{
+	"alternates": {
+		worktreeHash:  "a6b6ff89c593f042347113203ead1c14ab5733ce",
+		archiveDigest: "7d0cbf799462a3d0ccad8a0986a604003754dcfd",
+	},
+	"dirty": {
+		worktreeHash:  "7203669c66103305e56b9dcdf940a7fbeb515f28",
+		archiveDigest: "7627f12e403c2781da1368a5410f26e017ce2c04",
+	},
+	"linked-worktree": {
+		worktreeHash:  "363d996b02d9c3b598f0176619f5c6a44a82480a",
+		archiveDigest: "be2a54c56a29d9f9a1f72c635bf5fd6c7318a6bc",
+	},
+	"commit-graph":           {objectsCount: 31, packObjects: 30},
+	"commit-graph-chain":     {objectsCount: 31, packObjects: 30},
+	"sha256-scanner-entries": {objectsCount: 5, packObjects: 6},
+	"sha256-basic":           {objectsCount: 31, packObjects: 36},
+}
+
+// Verify checks the fixture's data files against the hashes and counts it
+// declares:
+//
+//   - the digest of the .git and worktree archives against DotGitHash and
+//     WorktreeHash;
+//   - the packfile trailer against its content and PackfileHash, and its
+//     header against ObjectsCount, when set;
+//   - the idx and rev files checksums, the packfile checksum they embed, and
+//     their content against the one generated from the packfile.
+//
+// The worktree archives of the dirty, alternates and linked-worktree fixtures
+// are not named after their digest, and the packfiles of the commit-graph,
+// commit-graph-chain, sha256-scanner-entries and sha256-basic fixtures do not
+// hold ObjectsCount objects. These exact mismatches are known exceptions,
+// kept so as not to change published fixtures, and are not reported.
+//
+// All other problems found are returned, joined.
+func (f *Fixture) Verify() error {
+	var errs []error
+
+	if f.DotGitHash != "" {
+		errs = append(errs, f.verifyArchive(fmt.Sprintf("data/git-%s.tgz", f.DotGitHash), f.DotGitHash))
+	}
+
+	if f.WorktreeHash != "" {
+		errs = append(errs, f.verifyArchive(fmt.Sprintf("data/worktree-%s.tgz", f.WorktreeHash), f.WorktreeHash))
+	}
+
+	if f.PackfileHash != "" {
+		errs = append(errs, f.verifyPackfile())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Verify calls Verify on every fixture, returning all problems found.
+func (g Fixtures) Verify() error {
+	var errs []error
+
+	for _, f := range g {
+		err := f.Verify()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("fixture %s: %w", f.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// verifyArchive checks the digest of the archive name, which is a SHA-1 or
+// SHA-256 depending on the length of hash.
+func (f *Fixture) verifyArchive(name, hash string) error {
+	format := object.FormatSHA1
+	if len(hash) == hex.EncodedLen(sha256.Size) {
+		format = object.FormatSHA256
+	}
+
+	h, err := object.NewHasher(format)
+	if err != nil {
+		return err
+	}
+
+	file, err := f.filesystem().Open(name)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = io.Copy(h, file)
+	if err != nil {
+		return err
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	known := knownMismatches[f.Name]
+	if sum != hash && (hash != known.worktreeHash || sum != known.archiveDigest) {
+		return fmt.Errorf("%w: %s has digest %s", ErrChecksumMismatch, name, sum)
+	}
+
+	return nil
+}
+
+func (f *Fixture) verifyPackfile() error {
+	name := f.packfilePath("pack")
+
+	data, err := f.readFile(name)
+	if err != nil {
+		return err
+	}
+
+	checksum, err := verifyTrailer(f.ObjectFormat, name, data)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	// The thin pack fixture is not named after its checksum.
+	if hex.EncodeToString(checksum) != f.PackfileHash && !f.Is(TagThinpack) {
+		errs = append(errs, fmt.Errorf("%w: %s has checksum %x", ErrChecksumMismatch, name, checksum))
+	}
+
+	header, err := packfile.ReadHeader(data)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+
+	known := knownMismatches[f.Name]
+	if f.ObjectsCount != 0 && int64(header.Objects) != int64(f.ObjectsCount) &&
+		(f.ObjectsCount != known.objectsCount || int64(header.Objects) != int64(known.packObjects)) {
+		errs = append(errs, fmt.Errorf("%w: %s has %d objects, want %d",
+			ErrObjectsCountMismatch, name, header.Objects, f.ObjectsCount))
+	}
+
+	errs = append(errs, f.verifyIndexes(checksum))
+
+	return errors.Join(errs...)
+}
+
+// verifyIndexes checks the idx and rev files of the packfile with the given
+// checksum, if present.
+func (f *Fixture) verifyIndexes(packChecksum []byte) error {
+	idx, err := f.readIndexFile("idx", packChecksum)
+	if err != nil || idx == nil {
+		return err
+	}
+
+	rev, err := f.readIndexFile("rev", packChecksum)
+	if err != nil {
+		return err
+	}
+
+	objects, err := f.objects()
+	if err != nil {
+		return err
+	}
+
+	entries, err := f.scan()
+	if err != nil {
+		return err
+	}
+
+	crcs := make(map[int64]uint32, len(entries))
+	for _, e := range entries {
+		crcs[e.Offset] = e.CRC32
+	}
+
+	idxEntries := make([]idxfile.Entry, 0, len(objects))
+	revEntries := make([]revfile.Entry, 0, len(objects))
+
+	for _, o := range objects {
+		idxEntries = append(idxEntries, idxfile.Entry{Hash: o.Hash, Offset: o.Offset, CRC32: crcs[o.Offset]})
+		revEntries = append(revEntries, revfile.Entry{Hash: o.Hash, Offset: o.Offset})
+	}
+
+	var errs []error
+
+	threshold := idxfile.LargeOffsetThreshold
+	if f.largeOffsetThreshold != 0 {
+		threshold = f.largeOffsetThreshold
+	}
+
+	want, err := idxfile.EncodeWithThreshold(f.ObjectFormat, idxEntries, packChecksum, threshold)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(idx, want) {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrContentMismatch, f.packfilePath("idx")))
+	}
+
+	if rev != nil {
+		want, err = revfile.Encode(f.ObjectFormat, revEntries, packChecksum)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(rev, want) {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrContentMismatch, f.packfilePath("rev")))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// readIndexFile reads the idx or rev file of the packfile, checking its
+// trailer and the packfile checksum preceding it. It returns nil if the
+// file does not exist.
+func (f *Fixture) readIndexFile(ext string, packChecksum []byte) ([]byte, error) {
+	name := f.packfilePath(ext)
+
+	data, err := f.readFile(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = verifyTrailer(f.ObjectFormat, name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	end := len(data) - len(packChecksum)*2
+	if end < 0 || !bytes.Equal(data[end:end+len(packChecksum)], packChecksum) {
+		return nil, fmt.Errorf("%w: %s does not reference the packfile", ErrChecksumMismatch, name)
+	}
+
+	return data, nil
+}
+
+// verifyTrailer checks that data ends with the hash of its preceding content,
+// and returns it.
+func verifyTrailer(format, name string, data []byte) ([]byte, error) {
+	h, err := object.NewHasher(format)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < h.Size() {
+		return nil, fmt.Errorf("%w: %s is too short", ErrChecksumMismatch, name)
+	}
+
+	end := len(data) - h.Size()
+	h.Write(data[:end])
+
+	if !bytes.Equal(h.Sum(nil), data[end:]) {
+		return nil, fmt.Errorf("%w: %s has a corrupt trailer", ErrChecksumMismatch, name)
+	}
+
+	return data[end:], nil
+}
@@ -0,0 +1,149 @@
+package fixtures
+
+import (
+	"testing"
+
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git-fixtures/v6/internal/idxfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyCorrupt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		corrupt func(t *testing.T, f *Fixture)
+		want    error
+	}{
+		{name: "pack trailer", corrupt: flipByte("pack", -1), want: ErrChecksumMismatch},
+		{name: "pack content", corrupt: flipByte("pack", 20), want: ErrChecksumMismatch},
+		{name: "idx trailer", corrupt: flipByte("idx", -1), want: ErrChecksumMismatch},
+		{name: "rev trailer", corrupt: flipByte("rev", -1), want: ErrChecksumMismatch},
+		{name: "packfile hash", corrupt: renamePackfile, want: ErrChecksumMismatch},
+		{name: "idx content", corrupt: rewriteIdx, want: ErrContentMismatch},
+		{
+			name:    "objects count",
+			corrupt: func(_ *testing.T, f *Fixture) { f.ObjectsCount++ },
+			want:    ErrObjectsCountMismatch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := newVerifyFixture(t)
+			require.NoError(t, f.Verify())
+
+			tc.corrupt(t, f)
+			assert.ErrorIs(t, f.Verify(), tc.want)
+		})
+	}
+}
+
+func TestVerifyArchive(t *testing.T) {
+	t.Parallel()
+
+	f := fixtures.ByTag("packfile").ByTag(".git").Exclude("multi-packfile").One()
+	require.NotNil(t, f)
+	require.NoError(t, f.verifyArchive(f.dotGitArchive(), f.DotGitHash))
+
+	err := f.verifyArchive(f.dotGitArchive(), "0000000000000000000000000000000000000000")
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+}
+
+func TestVerifyKnownMismatches(t *testing.T) {
+	t.Parallel()
+
+	for name, known := range knownMismatches {
+		f := ByName(name)
+		require.NotNil(t, f, name)
+		require.NoError(t, f.Verify(), name)
+
+		if known.objectsCount != 0 {
+			// Neither the published count nor the actual one.
+			f.ObjectsCount = known.objectsCount + known.packObjects
+			require.ErrorIs(t, f.Verify(), ErrObjectsCountMismatch, name)
+		}
+	}
+
+	f := ByName("dirty")
+	err := f.verifyArchive("data/worktree-"+f.WorktreeHash+".tgz", "0000000000000000000000000000000000000000")
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+}
+
+func newVerifyFixture(t *testing.T) *Fixture {
+	t.Helper()
+
+	b := NewBuilder(objectFormatSHA1)
+	blob := b.Blob([]byte("content"))
+	tree := b.Tree(TreeEntry{Name: "file", Mode: ModeRegular, Hash: blob})
+	commit := b.Commit(CommitSpec{Tree: tree, Message: "commit\n"})
+	b.Ref("refs/heads/master", commit)
+
+	f, err := b.Packfile()
+	require.NoError(t, err)
+
+	return f
+}
+
+// flipByte returns a function inverting the byte at offset of the fixture's
+// file with extension ext. Negative offsets count from the end.
+func flipByte(ext string, offset int) func(t *testing.T, f *Fixture) {
+	return func(t *testing.T, f *Fixture) {
+		t.Helper()
+
+		name := f.packfilePath(ext)
+
+		data, err := util.ReadFile(f.fs, name)
+		require.NoError(t, err)
+
+		i := offset
+		if i < 0 {
+			i += len(data)
+		}
+
+		data[i] ^= 0xff
+
+		err = util.WriteFile(f.fs, name, data, 0o644)
+		require.NoError(t, err)
+	}
+}
+
+// renamePackfile moves the fixture's pack files to a name which is not their
+// checksum.
+func renamePackfile(t *testing.T, f *Fixture) {
+	t.Helper()
+
+	old := *f
+	f.PackfileHash = "0000000000000000000000000000000000000000"
+
+	for _, ext := range []string{"pack", "idx", "rev"} {
+		require.NoError(t, f.fs.Rename(old.packfilePath(ext), f.packfilePath(ext)))
+	}
+}
+
+// rewriteIdx replaces the fixture's idx file with a well-formed one holding
+// wrong CRC32s.
+func rewriteIdx(t *testing.T, f *Fixture) {
+	t.Helper()
+
+	objects, err := f.objects()
+	require.NoError(t, err)
+
+	entries := make([]idxfile.Entry, 0, len(objects))
+	for _, o := range objects {
+		entries = append(entries, idxfile.Entry{Hash: o.Hash, Offset: o.Offset, CRC32: 0})
+	}
+
+	pack, err := f.readFile(f.packfilePath("pack"))
+	require.NoError(t, err)
+
+	idx, err := idxfile.Encode(f.ObjectFormat, entries, pack[len(pack)-20:])
+	require.NoError(t, err)
+
+	err = util.WriteFile(f.fs, f.packfilePath("idx"), idx, 0o644)
+	require.NoError(t, err)
+}
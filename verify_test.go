@@ -0,0 +1,34 @@
+package fixtures_test
+
+import (
+	"testing"
+
+	fixtures "github.com/go-git/go-git-fixtures/v6"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range fixtures.All() {
+		t.Run(f.Name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NoError(t, f.Verify())
+		})
+	}
+}
+
+func TestVerifyBuilderPackfile(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{"sha1", "sha256"} {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := newHistoryBuilder(t, format, 5).Packfile(fixtures.WithDelta(fixtures.DeltaOFS))
+			require.NoError(t, err)
+			require.NoError(t, f.Verify())
+		})
+	}
+}